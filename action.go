@@ -88,11 +88,8 @@ type GetVersionResponse struct {
 
 // GetStatusResponse get_status 动作的响应数据
 type GetStatusResponse struct {
-	Good bool `json:"good"` // 是否各项状态都符合预期
-	Bots []struct {
-		Self   Self `json:"self"`   // 机器人自身标识
-		Online bool `json:"online"` // 是否在线
-	} `json:"bots"` // 机器人账号状态列表
+	Good bool      `json:"good"` // 是否各项状态都符合预期
+	Bots []BotInfo `json:"bots"` // 机器人账号状态列表
 }
 
 // GetSupportedActionsResponse get_supported_actions 动作的响应数据
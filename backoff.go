@@ -0,0 +1,67 @@
+package onebot
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff 重连退避策略
+type Backoff interface {
+	// Delay 返回第 attempt 次（从 1 开始）重连前的等待时间
+	Delay(attempt int) time.Duration
+}
+
+// ConstantBackoff 固定间隔退避策略
+type ConstantBackoff time.Duration
+
+// Delay 实现 Backoff 接口
+func (b ConstantBackoff) Delay(int) time.Duration {
+	return time.Duration(b)
+}
+
+// ExponentialBackoff 指数退避策略
+type ExponentialBackoff struct {
+	Initial    time.Duration // 首次重连前的等待时间
+	Max        time.Duration // 最大等待时间，0 表示不限制
+	Multiplier float64       // 每次重连等待时间的增长倍数，不大于 1 时按 2 计算
+}
+
+// Delay 实现 Backoff 接口
+func (b ExponentialBackoff) Delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+	if b.Max > 0 && delay > float64(b.Max) {
+		return b.Max
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(delay)
+}
+
+// reconnectDelay 计算第 attempt 次重连前的等待时间（含抖动）
+func (c *Client) reconnectDelay(attempt int) time.Duration {
+	backoff := c.backoff
+	if backoff == nil {
+		backoff = ConstantBackoff(c.reconnectWait)
+	}
+
+	return applyJitter(backoff.Delay(attempt), c.jitter)
+}
+
+// applyJitter 在 [d*(1-ratio), d*(1+ratio)] 范围内随机取值
+func applyJitter(d time.Duration, ratio float64) time.Duration {
+	if ratio <= 0 || d <= 0 {
+		return d
+	}
+	ratio = min(ratio, 1)
+
+	delta := float64(d) * ratio
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
@@ -0,0 +1,250 @@
+package onebot
+
+import (
+	"context"
+	"time"
+)
+
+// Bot 绑定到指定机器人账号的操作句柄
+// 一个 OneBot 连接可以承载多个机器人账号，通过 Bot 调用的动作都会携带该账号的 self，
+// 通过 Bot 注册的事件处理器只处理 Event.Self 与该账号一致的事件。
+// get_version、get_status、get_latest_events 等面向实现的元动作请直接通过 Client 调用。
+type Bot struct {
+	client *Client
+	self   Self
+}
+
+// Bot 返回绑定到指定机器人账号的操作句柄
+func (c *Client) Bot(self Self) *Bot {
+	return &Bot{
+		client: c,
+		self:   self,
+	}
+}
+
+// selfKey 上下文中机器人账号的键
+type selfKey struct{}
+
+// ContextWithSelf 返回携带机器人账号的上下文
+// 使用该上下文调用 CallContext 或各动作的 Context 版本时，会以该账号代替 WithSelf 的设置
+func ContextWithSelf(ctx context.Context, self Self) context.Context {
+	return context.WithValue(ctx, selfKey{}, self)
+}
+
+// selfFromContext 返回上下文中携带的机器人账号
+func selfFromContext(ctx context.Context) (Self, bool) {
+	self, ok := ctx.Value(selfKey{}).(Self)
+	return self, ok
+}
+
+// Self 返回绑定的机器人账号
+func (b *Bot) Self() Self {
+	return b.self
+}
+
+// Client 返回所属的客户端
+func (b *Bot) Client() *Client {
+	return b.client
+}
+
+// IsOnline 判断该账号当前是否在线
+func (b *Bot) IsOnline() bool {
+	return b.client.IsOnline(b.self)
+}
+
+// On 注册只处理该账号事件的处理器，eventType 的含义与 Client.On 相同
+func (b *Bot) On(eventType string, handler EventHandler) *Subscription {
+	return b.client.On(eventType, func(event any) {
+		if b.owns(event) {
+			handler(event)
+		}
+	})
+}
+
+// HandleFunc 注册只处理该账号事件、返回错误的处理器，eventType 的含义与 Client.On 相同
+func (b *Bot) HandleFunc(eventType string, handler HandlerFunc) *Subscription {
+	return b.client.HandleFunc(eventType, func(event any) error {
+		if b.owns(event) {
+			return handler(event)
+		}
+		return nil
+	})
+}
+
+// Once 注册只处理一次该账号事件的处理器，eventType 的含义与 Client.On 相同
+func (b *Bot) Once(eventType string, handler EventHandler) *Subscription {
+	return b.client.once(eventType, b.owns, handler)
+}
+
+// owns 判断事件是否属于该账号
+func (b *Bot) owns(event any) bool {
+	e, ok := event.(interface{ Base() *Event })
+	if !ok {
+		return false
+	}
+	self := e.Base().Self
+	return self != nil && *self == b.self
+}
+
+// context 为上下文绑定该账号
+func (b *Bot) context(ctx context.Context) context.Context {
+	return ContextWithSelf(ctx, b.self)
+}
+
+// Call 以该账号调用动作（使用默认超时时间）
+func (b *Bot) Call(action string, params map[string]any) (*ActionResponse, error) {
+	return b.client.CallContext(b.context(context.Background()), action, params)
+}
+
+// CallWithTimeout 以该账号调用动作（带超时）
+func (b *Bot) CallWithTimeout(action string, params map[string]any, timeout time.Duration) (*ActionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return b.client.CallContext(b.context(ctx), action, params)
+}
+
+// CallContext 以该账号调用动作（带上下文）
+func (b *Bot) CallContext(ctx context.Context, action string, params map[string]any) (*ActionResponse, error) {
+	return b.client.CallContext(b.context(ctx), action, params)
+}
+
+// SendPrivateMessage 发送私聊消息
+func (b *Bot) SendPrivateMessage(userID string, message Message) (*SendMessageResponse, error) {
+	return b.client.SendPrivateMessageContext(b.context(context.Background()), userID, message)
+}
+
+// SendPrivateMessageContext 发送私聊消息（带上下文）
+func (b *Bot) SendPrivateMessageContext(ctx context.Context, userID string, message Message) (*SendMessageResponse, error) {
+	return b.client.SendPrivateMessageContext(b.context(ctx), userID, message)
+}
+
+// SendGroupMessage 发送群消息
+func (b *Bot) SendGroupMessage(groupID string, message Message) (*SendMessageResponse, error) {
+	return b.client.SendGroupMessageContext(b.context(context.Background()), groupID, message)
+}
+
+// SendGroupMessageContext 发送群消息（带上下文）
+func (b *Bot) SendGroupMessageContext(ctx context.Context, groupID string, message Message) (*SendMessageResponse, error) {
+	return b.client.SendGroupMessageContext(b.context(ctx), groupID, message)
+}
+
+// SendMessage 通用发送消息
+func (b *Bot) SendMessage(detailType string, params map[string]any) (*SendMessageResponse, error) {
+	return b.client.SendMessageContext(b.context(context.Background()), detailType, params)
+}
+
+// SendMessageContext 通用发送消息（带上下文）
+func (b *Bot) SendMessageContext(ctx context.Context, detailType string, params map[string]any) (*SendMessageResponse, error) {
+	return b.client.SendMessageContext(b.context(ctx), detailType, params)
+}
+
+// DeleteMessage 撤回消息
+func (b *Bot) DeleteMessage(messageID string) error {
+	return b.client.DeleteMessageContext(b.context(context.Background()), messageID)
+}
+
+// DeleteMessageContext 撤回消息（带上下文）
+func (b *Bot) DeleteMessageContext(ctx context.Context, messageID string) error {
+	return b.client.DeleteMessageContext(b.context(ctx), messageID)
+}
+
+// GetSupportedActions 获取支持的动作列表
+func (b *Bot) GetSupportedActions() ([]string, error) {
+	return b.client.GetSupportedActionsContext(b.context(context.Background()))
+}
+
+// GetSupportedActionsContext 获取支持的动作列表（带上下文）
+func (b *Bot) GetSupportedActionsContext(ctx context.Context) ([]string, error) {
+	return b.client.GetSupportedActionsContext(b.context(ctx))
+}
+
+// GetSelfInfo 获取机器人自身信息
+func (b *Bot) GetSelfInfo() (*GetSelfInfoResponse, error) {
+	return b.client.GetSelfInfoContext(b.context(context.Background()))
+}
+
+// GetSelfInfoContext 获取机器人自身信息（带上下文）
+func (b *Bot) GetSelfInfoContext(ctx context.Context) (*GetSelfInfoResponse, error) {
+	return b.client.GetSelfInfoContext(b.context(ctx))
+}
+
+// GetUserInfo 获取用户信息
+func (b *Bot) GetUserInfo(userID string) (*GetUserInfoResponse, error) {
+	return b.client.GetUserInfoContext(b.context(context.Background()), userID)
+}
+
+// GetUserInfoContext 获取用户信息（带上下文）
+func (b *Bot) GetUserInfoContext(ctx context.Context, userID string) (*GetUserInfoResponse, error) {
+	return b.client.GetUserInfoContext(b.context(ctx), userID)
+}
+
+// GetFriendList 获取好友列表
+func (b *Bot) GetFriendList() ([]GetUserInfoResponse, error) {
+	return b.client.GetFriendListContext(b.context(context.Background()))
+}
+
+// GetFriendListContext 获取好友列表（带上下文）
+func (b *Bot) GetFriendListContext(ctx context.Context) ([]GetUserInfoResponse, error) {
+	return b.client.GetFriendListContext(b.context(ctx))
+}
+
+// GetGroupInfo 获取群信息
+func (b *Bot) GetGroupInfo(groupID string) (*GetGroupInfoResponse, error) {
+	return b.client.GetGroupInfoContext(b.context(context.Background()), groupID)
+}
+
+// GetGroupInfoContext 获取群信息（带上下文）
+func (b *Bot) GetGroupInfoContext(ctx context.Context, groupID string) (*GetGroupInfoResponse, error) {
+	return b.client.GetGroupInfoContext(b.context(ctx), groupID)
+}
+
+// GetGroupList 获取群列表
+func (b *Bot) GetGroupList() ([]GetGroupInfoResponse, error) {
+	return b.client.GetGroupListContext(b.context(context.Background()))
+}
+
+// GetGroupListContext 获取群列表（带上下文）
+func (b *Bot) GetGroupListContext(ctx context.Context) ([]GetGroupInfoResponse, error) {
+	return b.client.GetGroupListContext(b.context(ctx))
+}
+
+// GetGroupMemberInfo 获取群成员信息
+func (b *Bot) GetGroupMemberInfo(groupID, userID string) (*GetGroupMemberInfoResponse, error) {
+	return b.client.GetGroupMemberInfoContext(b.context(context.Background()), groupID, userID)
+}
+
+// GetGroupMemberInfoContext 获取群成员信息（带上下文）
+func (b *Bot) GetGroupMemberInfoContext(ctx context.Context, groupID, userID string) (*GetGroupMemberInfoResponse, error) {
+	return b.client.GetGroupMemberInfoContext(b.context(ctx), groupID, userID)
+}
+
+// GetGroupMemberList 获取群成员列表
+func (b *Bot) GetGroupMemberList(groupID string) ([]GetGroupMemberInfoResponse, error) {
+	return b.client.GetGroupMemberListContext(b.context(context.Background()), groupID)
+}
+
+// GetGroupMemberListContext 获取群成员列表（带上下文）
+func (b *Bot) GetGroupMemberListContext(ctx context.Context, groupID string) ([]GetGroupMemberInfoResponse, error) {
+	return b.client.GetGroupMemberListContext(b.context(ctx), groupID)
+}
+
+// UploadFile 上传文件
+func (b *Bot) UploadFile(fileType string, name string, url string) (*UploadFileResponse, error) {
+	return b.client.UploadFileContext(b.context(context.Background()), fileType, name, url)
+}
+
+// UploadFileContext 上传文件（带上下文）
+func (b *Bot) UploadFileContext(ctx context.Context, fileType string, name string, url string) (*UploadFileResponse, error) {
+	return b.client.UploadFileContext(b.context(ctx), fileType, name, url)
+}
+
+// GetFile 获取文件
+func (b *Bot) GetFile(fileID string, fileType string) (*GetFileResponse, error) {
+	return b.client.GetFileContext(b.context(context.Background()), fileID, fileType)
+}
+
+// GetFileContext 获取文件（带上下文）
+func (b *Bot) GetFileContext(ctx context.Context, fileID string, fileType string) (*GetFileResponse, error) {
+	return b.client.GetFileContext(b.context(ctx), fileID, fileType)
+}
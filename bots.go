@@ -0,0 +1,84 @@
+package onebot
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Bots 返回当前已知的所有机器人账号及其在线状态
+// 状态来自连接建立时的 get_status 动作和 meta.status_update 事件
+func (c *Client) Bots() []BotInfo {
+	c.botsMu.RLock()
+	defer c.botsMu.RUnlock()
+
+	bots := make([]BotInfo, 0, len(c.bots))
+	for self, online := range c.bots {
+		bots = append(bots, BotInfo{Self: self, Online: online})
+	}
+	slices.SortFunc(bots, func(a, b BotInfo) int {
+		return cmp.Or(cmp.Compare(a.Self.Platform, b.Self.Platform), cmp.Compare(a.Self.UserID, b.Self.UserID))
+	})
+
+	return bots
+}
+
+// IsOnline 判断指定机器人账号当前是否在线，未知账号返回 false
+func (c *Client) IsOnline(self Self) bool {
+	c.botsMu.RLock()
+	defer c.botsMu.RUnlock()
+
+	return c.bots[self]
+}
+
+// OnBotStatus 注册机器人账号状态变化回调
+// 账号上线、下线或从状态列表中消失（视为下线）时调用，回调应尽快返回
+func (c *Client) OnBotStatus(fn func(BotInfo)) {
+	c.botsMu.Lock()
+	defer c.botsMu.Unlock()
+
+	c.botHooks = append(c.botHooks, fn)
+}
+
+// refreshBots 通过 get_status 动作同步机器人账号状态
+func (c *Client) refreshBots() {
+	status, err := c.GetStatusContext(c.ctx)
+	if err != nil {
+		if !c.closed.Load() {
+			c.logger.Warn("同步机器人状态失败", "error", err)
+		}
+		return
+	}
+
+	c.updateBots(status.Bots)
+}
+
+// updateBots 以完整的状态列表更新机器人账号状态，并通知发生变化的账号
+func (c *Client) updateBots(bots []BotInfo) {
+	c.botsMu.Lock()
+	next := make(map[Self]bool, len(bots))
+	var changed []BotInfo
+	for _, bot := range bots {
+		next[bot.Self] = bot.Online
+		if online, ok := c.bots[bot.Self]; !ok || online != bot.Online {
+			changed = append(changed, bot)
+		}
+	}
+	for self, online := range c.bots {
+		if _, ok := next[self]; !ok && online {
+			changed = append(changed, BotInfo{Self: self, Online: false})
+		}
+	}
+	c.bots = next
+	hooks := slices.Clone(c.botHooks)
+	c.botsMu.Unlock()
+
+	for _, bot := range changed {
+		c.logger.Info("机器人状态变化",
+			"platform", bot.Self.Platform,
+			"user_id", bot.Self.UserID,
+			"online", bot.Online)
+		for _, fn := range hooks {
+			fn(bot)
+		}
+	}
+}
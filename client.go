@@ -3,8 +3,10 @@ package onebot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,29 +18,83 @@ import (
 // Client OneBot 12 客户端
 type Client struct {
 	// 配置
-	url           string
-	accessToken   string
-	self          *Self
-	logger        *slog.Logger
-	reconnect     bool
-	reconnectWait time.Duration
-	heartbeat     time.Duration
-	timeout       time.Duration
+	url                  string
+	accessToken          string
+	httpClient           *http.Client
+	self                 *Self
+	logger               *slog.Logger
+	reconnect            bool
+	reconnectWait        time.Duration
+	backoff              Backoff
+	jitter               float64
+	maxReconnectAttempts int
+	onGiveUp             func(attempts int, err error)
+	heartbeat            time.Duration
+	watchdog             bool
+	versionCheck         bool
+	timeout              time.Duration
+	polling              bool
+	pollLimit            int
+	pollTimeout          time.Duration
 
 	// 运行时状态
-	conn      *websocket.Conn
-	mu        sync.RWMutex
-	closed    atomic.Bool
-	connected atomic.Bool
+	conn          *websocket.Conn
+	mu            sync.RWMutex
+	closed        atomic.Bool
+	connected     atomic.Bool
+	reverse       atomic.Bool
+	accepting     atomic.Bool // 反向 WebSocket 连接槽位，从握手前占用到连接断开
+	connInfo      atomic.Pointer[GetVersionResponse]
+	rejected      atomic.Pointer[error]
+	server        *http.Server
+	writeOnce     sync.Once
+	watchdogTimer *time.Timer
+	watchdogMu    sync.Mutex
+
+	// 连接状态
+	state      ConnState
+	attempt    int
+	downSince  time.Time
+	stateHooks []func(ConnStateEvent)
+	stateMu    sync.Mutex
+
+	// 机器人账号状态
+	bots     map[Self]bool
+	botHooks []func(BotInfo)
+	botsMu   sync.RWMutex
 
 	// 事件处理
-	eventHandlers map[string][]EventHandler
+	eventHandlers map[string][]handlerEntry
+	middleware    []Middleware
+	keyMiddleware map[string][]Middleware
 	handlerMu     sync.RWMutex
+	subscribers   map[*Subscription]*subscriber
+	subMu         sync.RWMutex
+	quickAction   QuickActionFunc
+	dedup         DedupStore
+	dedupTTL      time.Duration
+	errorHandler  func(event any, err error)
+	dispatcher    *dispatcher
+	workers       int
+	queueSize     int
+	overflow      OverflowPolicy
+	orderKeyFunc  func(event any) string
+
+	// 会话
+	sessions       map[string]*Session
+	sessionMu      sync.RWMutex
+	sessionTimeout time.Duration
+	cancelKeywords []string
 
 	// 动作处理
-	actionChan   chan *actionCall
-	responseChan map[string]chan *ActionResponse
-	responseMu   sync.RWMutex
+	actionChan    chan *actionCall
+	responseChan  map[string]*actionCall
+	responseMu    sync.RWMutex
+	resendActions map[string]bool
+	interceptors  []ActionInterceptor
+	interceptorMu sync.RWMutex
+	held          []*actionCall
+	heldMu        sync.Mutex
 
 	// 上下文
 	ctx    context.Context
@@ -48,28 +104,51 @@ type Client struct {
 // EventHandler 事件处理函数
 type EventHandler func(event any)
 
+// HandlerFunc 返回错误的事件处理函数，返回的错误会交给 WithErrorHandler 设置的错误处理函数
+type HandlerFunc func(event any) error
+
 // actionCall 内部动作调用结构
 type actionCall struct {
 	request  *ActionRequest
 	response chan *ActionResponse
+	err      chan error
+	sent     atomic.Bool
+}
+
+// fail 以错误结束动作调用
+func (call *actionCall) fail(err error) {
+	select {
+	case call.err <- err:
+	default:
+	}
 }
 
 // New 创建新的 OneBot 客户端
+// url 为 ws:// 或 wss:// 时使用正向 WebSocket，为 http:// 或 https:// 时通过 HTTP 调用动作；
+// 仅使用反向 WebSocket 时可传空字符串
 func New(url string, opts ...Option) (*Client, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c := &Client{
-		url:           url,
-		logger:        slog.Default(),
-		reconnect:     true,
-		reconnectWait: 5 * time.Second,
-		heartbeat:     30 * time.Second,
-		timeout:       30 * time.Second,
-		eventHandlers: make(map[string][]EventHandler),
-		actionChan:    make(chan *actionCall, 100),
-		responseChan:  make(map[string]chan *ActionResponse),
-		ctx:           ctx,
-		cancel:        cancel,
+		url:            url,
+		logger:         slog.Default(),
+		httpClient:     http.DefaultClient,
+		reconnect:      true,
+		reconnectWait:  5 * time.Second,
+		heartbeat:      30 * time.Second,
+		watchdog:       true,
+		queueSize:      100,
+		overflow:       OverflowDropNewest,
+		timeout:        30 * time.Second,
+		eventHandlers:  make(map[string][]handlerEntry),
+		keyMiddleware:  make(map[string][]Middleware),
+		subscribers:    make(map[*Subscription]*subscriber),
+		sessions:       make(map[string]*Session),
+		sessionTimeout: 5 * time.Minute,
+		actionChan:     make(chan *actionCall, 100),
+		responseChan:   make(map[string]*actionCall),
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 
 	// 应用选项
@@ -77,23 +156,30 @@ func New(url string, opts ...Option) (*Client, error) {
 		opt(c)
 	}
 
+	if c.workers > 0 {
+		c.dispatcher = newDispatcher(ctx, c.workers, c.queueSize, c.overflow, c.logger)
+		c.dispatcher.start()
+	}
+
 	return c, nil
 }
 
 // Connect 连接到 OneBot 实现
+// 使用 HTTP 通信方式（http:// 或 https:// 地址）时无需建立连接，若启用了 WithPolling 则开始轮询事件
 func (c *Client) Connect() error {
+	if c.isHTTP() {
+		c.logger.Info("使用 HTTP 通信方式", "url", c.url)
+		go c.refreshBots()
+		if c.polling {
+			go c.pollLoop()
+		}
+		return nil
+	}
 	return c.connect()
 }
 
 // connect 内部连接方法
 func (c *Client) connect() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.conn != nil {
-		c.conn.CloseNow()
-	}
-
 	// 设置请求头
 	opts := &websocket.DialOptions{
 		HTTPHeader: make(map[string][]string),
@@ -108,33 +194,78 @@ func (c *Client) connect() error {
 		return fmt.Errorf("连接 WebSocket 失败: %w", err)
 	}
 
+	c.attach(conn)
+	c.logger.Info("已连接到 OneBot 实现", "url", c.url)
+
+	go c.readLoop(conn)
+
+	return nil
+}
+
+// attach 将已建立的 WebSocket 连接设为当前连接，旧连接会被关闭
+func (c *Client) attach(conn *websocket.Conn) {
 	// 取消读取限制
 	conn.SetReadLimit(-1)
 
+	c.mu.Lock()
+	old := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	// 旧连接上已发送的动作收不到响应，立即结束或等待在新连接上重发
+	if old != nil {
+		old.CloseNow()
+		c.failPending()
+	}
+
+	c.mu.Lock()
 	c.conn = conn
+	c.mu.Unlock()
+
+	// 心跳超时检测和实现信息都从新连接重新开始
+	c.stopWatchdog()
+	c.connInfo.Store(nil)
+	c.rejected.Store(nil)
 	c.connected.Store(true)
-	c.logger.Info("已连接到 OneBot 实现", "url", c.url)
 
-	// 启动读写协程
-	go c.readLoop()
-	go c.writeLoop()
+	// 写协程在所有连接间共享，只需启动一次
+	c.writeOnce.Do(func() {
+		go c.writeLoop()
+	})
 
-	return nil
+	c.setState(StateConnected, 0, nil)
+
+	go c.resendHeld()
+	go c.refreshBots()
 }
 
 // readLoop 读取消息循环
-func (c *Client) readLoop() {
+func (c *Client) readLoop(conn *websocket.Conn) {
+	var readErr error
 	defer func() {
-		c.connected.Store(false)
-		c.handleDisconnect()
+		// 连接已被新连接替换时不视为断开
+		c.mu.Lock()
+		current := c.conn == conn
+		if current {
+			c.conn = nil
+		}
+		c.mu.Unlock()
+
+		if current {
+			c.connected.Store(false)
+			c.stopWatchdog()
+			c.failPending()
+			c.handleDisconnect(readErr)
+		}
 	}()
 
 	for {
-		_, data, err := c.conn.Read(c.ctx)
+		_, data, err := conn.Read(c.ctx)
 		if err != nil {
 			if !c.closed.Load() {
 				c.logger.Error("读取消息失败", "error", err)
 			}
+			readErr = err
 			return
 		}
 
@@ -158,33 +289,22 @@ func (c *Client) readLoop() {
 
 // writeLoop 写入消息循环
 func (c *Client) writeLoop() {
-	ticker := time.NewTicker(c.heartbeat)
-	defer ticker.Stop()
-
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 
-		case <-ticker.C:
-			// 发送心跳（如果需要）
-			// OneBot 12 标准中心跳是通过元事件推送的，不需要主动发送
-
 		case call := <-c.actionChan:
-			// 设置 echo
-			if call.request.Echo == "" {
-				call.request.Echo = uuid.New().String()
+			// 调用方已取消或超时的动作不再发送，避免调用方重试时重复执行
+			if !c.registered(call.request.Echo) {
+				continue
 			}
 
-			// 注册响应通道
-			c.responseMu.Lock()
-			c.responseChan[call.request.Echo] = call.response
-			c.responseMu.Unlock()
-
 			// 发送请求
 			data, err := json.Marshal(call.request)
 			if err != nil {
 				c.logger.Error("序列化动作请求失败", "error", err)
+				c.removeResponseChan(call.request.Echo)
 				call.response <- &ActionResponse{
 					Status:  "failed",
 					Retcode: RetcodeBadRequest,
@@ -197,126 +317,203 @@ func (c *Client) writeLoop() {
 			conn := c.conn
 			c.mu.RUnlock()
 
-			if conn != nil {
-				err = conn.Write(c.ctx, websocket.MessageText, data)
-				if err != nil {
-					c.logger.Error("发送动作请求失败", "error", err)
-					call.response <- &ActionResponse{
-						Status:  "failed",
-						Retcode: RetcodeInternalHandlerError,
-						Message: err.Error(),
-					}
-				} else {
-					c.logger.Debug("发送动作请求", "action", call.request.Action)
+			// 连接已断开，排队中的动作立即失败或等待重连后重发
+			if conn == nil {
+				c.holdOrFail(call)
+				continue
+			}
+
+			// 写入前标记为已发送，使写入期间断开连接时 failPending 能处理该调用
+			call.sent.Store(true)
+			if err := conn.Write(c.ctx, websocket.MessageText, data); err != nil {
+				c.logger.Error("发送动作请求失败", "error", err)
+				// failPending 可能已经处理过该调用
+				if call.sent.CompareAndSwap(true, false) {
+					c.holdOrFail(call)
 				}
+				continue
 			}
+
+			c.logger.Debug("发送动作请求", "action", call.request.Action)
 		}
 	}
 }
 
-// handleEvent 处理事件
-func (c *Client) handleEvent(event any) {
-	c.handlerMu.RLock()
-	defer c.handlerMu.RUnlock()
+// handleEvent 处理事件，事件重复或被进行中的会话接收时返回 false
+func (c *Client) handleEvent(event any) bool {
+	if c.duplicate(event) {
+		return false
+	}
+
+	if meta, ok := event.(*MetaEvent); ok {
+		c.handleMetaEvent(meta)
+	}
+
+	keys := eventKeys(event)
 
-	// 获取事件类型
-	var eventType string
 	switch e := event.(type) {
 	case *MessageEvent:
-		eventType = "message"
-		if e.DetailType != "" {
-			eventType = "message." + e.DetailType
-		}
 		c.logger.Info("收到消息事件",
 			"type", e.DetailType,
 			"user_id", e.UserID,
 			"message", e.AltMessage)
-	case *NoticeEvent:
-		eventType = "notice"
-		if e.DetailType != "" {
-			eventType = "notice." + e.DetailType
-		}
-		c.logger.Info("收到通知事件", "type", e.DetailType)
+	case AnyNotice:
+		c.logger.Info("收到通知事件", "type", e.Notice().DetailType)
 	case *MetaEvent:
-		eventType = "meta"
-		if e.DetailType != "" {
-			eventType = "meta." + e.DetailType
-		}
 		c.logger.Debug("收到元事件", "type", e.DetailType)
 	case *RequestEvent:
-		eventType = "request"
-		if e.DetailType != "" {
-			eventType = "request." + e.DetailType
-		}
 		c.logger.Info("收到请求事件", "type", e.DetailType)
-	case *Event:
-		eventType = e.Type
-		if e.DetailType != "" {
-			eventType = e.Type + "." + e.DetailType
-		}
-		c.logger.Info("收到事件", "type", eventType)
+	default:
+		c.logger.Info("收到事件", "type", keys[len(keys)-1])
+	}
+
+	// 进行中的会话优先接收所属的消息
+	if msg, ok := event.(*MessageEvent); ok && c.deliverSession(msg) {
+		return false
+	}
+
+	c.publish(event)
+
+	// 没有中间件且没有匹配的处理器时直接忽略
+	c.handlerMu.RLock()
+	middleware := c.middleware
+	matched := len(middleware) > 0
+	for _, key := range keys {
+		matched = matched || len(c.eventHandlers[key]) > 0
 	}
+	c.handlerMu.RUnlock()
 
-	// 调用通用处理器
-	if handlers, ok := c.eventHandlers["*"]; ok {
-		for _, handler := range handlers {
-			go handler(event)
+	if !matched {
+		return true
+	}
+
+	// 同一事件的中间件和处理器在同一协程中按注册顺序依次执行
+	next := chain(c.dispatchHandlers, middleware)
+	if c.dispatcher == nil {
+		go c.invoke(next, event)
+		return true
+	}
+
+	c.dispatcher.submit(c.orderKey(event), func() {
+		c.invoke(next, event)
+	})
+	return true
+}
+
+// dispatchHandlers 将事件交给匹配的处理器，位于全局中间件链的末端
+// 中间件可能替换事件，因此按传入的事件重新匹配处理器；返回所有处理器的错误
+func (c *Client) dispatchHandlers(event any) error {
+	c.handlerMu.RLock()
+	var groups []HandlerFunc
+	for _, key := range eventKeys(event) {
+		handlers := c.eventHandlers[key]
+		if len(handlers) == 0 {
+			continue
 		}
+		groups = append(groups, chain(runHandlers(handlers), c.keyMiddleware[key]))
+	}
+	c.handlerMu.RUnlock()
+
+	var errs []error
+	for _, group := range groups {
+		errs = append(errs, safeCall(group, event))
 	}
+	return errors.Join(errs...)
+}
 
-	// 调用类型处理器
-	if handlers, ok := c.eventHandlers[eventType]; ok {
-		for _, handler := range handlers {
-			go handler(event)
+// runHandlers 返回按注册顺序依次执行一组处理器的函数，返回所有处理器的错误
+// 单个处理器的 panic 转换为 *PanicError，不影响其余处理器
+func runHandlers(handlers []handlerEntry) HandlerFunc {
+	return func(event any) error {
+		var errs []error
+		for _, entry := range handlers {
+			errs = append(errs, safeCall(entry.handler, event))
 		}
+		return errors.Join(errs...)
 	}
 }
 
 // handleActionResponse 处理动作响应
 func (c *Client) handleActionResponse(response *ActionResponse) {
 	c.responseMu.Lock()
-	ch, ok := c.responseChan[response.Echo]
+	call, ok := c.responseChan[response.Echo]
 	if ok {
 		delete(c.responseChan, response.Echo)
 	}
 	c.responseMu.Unlock()
 
-	if ok && ch != nil {
+	if ok {
 		c.logger.Debug("收到动作响应",
 			"echo", response.Echo,
 			"status", response.Status,
 			"retcode", response.Retcode)
 		select {
-		case ch <- response:
-		case <-time.After(time.Second):
-			c.logger.Error("发送响应到通道超时", "echo", response.Echo)
+		case call.response <- response:
+		default:
+			c.logger.Error("发送响应到通道失败", "echo", response.Echo)
 		}
 	} else {
 		c.logger.Warn("收到未知的动作响应", "echo", response.Echo)
 	}
 }
 
-// handleDisconnect 处理断开连接
-func (c *Client) handleDisconnect() {
+// handleDisconnect 处理断开连接，err 为导致断开的错误
+func (c *Client) handleDisconnect(err error) {
 	if c.closed.Load() {
 		return
 	}
 
 	c.logger.Warn("WebSocket 连接断开")
 
-	if c.reconnect {
-		go func() {
-			for !c.closed.Load() {
-				c.logger.Info("尝试重连...", "wait", c.reconnectWait)
-				time.Sleep(c.reconnectWait)
+	// 版本校验失败时重连也无济于事，直接放弃
+	if rejected := c.rejected.Swap(nil); rejected != nil {
+		err = *rejected
+		c.setState(StateDisconnected, 0, err)
+		if !c.reverse.Load() {
+			c.logger.Error("OneBot 版本不匹配，放弃重连", "error", err)
+			c.giveUp(0, err)
+		}
+		return
+	}
 
-				if err := c.connect(); err != nil {
-					c.logger.Error("重连失败", "error", err)
-				} else {
-					break
-				}
-			}
-		}()
+	c.setState(StateDisconnected, 0, err)
+
+	// 反向 WebSocket 由 OneBot 实现负责重连
+	if c.reconnect && !c.reverse.Load() {
+		go c.reconnectLoop()
+	}
+}
+
+// reconnectLoop 按退避策略重连，直到成功、客户端关闭或达到最大重连次数
+func (c *Client) reconnectLoop() {
+	var lastErr error
+	for attempt := 1; !c.closed.Load(); attempt++ {
+		if c.maxReconnectAttempts > 0 && attempt > c.maxReconnectAttempts {
+			c.logger.Error("重连次数已达上限，放弃重连", "attempts", c.maxReconnectAttempts, "error", lastErr)
+			c.giveUp(c.maxReconnectAttempts, lastErr)
+			return
+		}
+
+		c.setState(StateReconnecting, attempt, lastErr)
+		wait := c.reconnectDelay(attempt)
+		c.logger.Info("尝试重连...", "attempt", attempt, "wait", wait)
+		if !c.sleep(wait) {
+			return
+		}
+
+		if lastErr = c.connect(); lastErr == nil {
+			return
+		}
+		c.logger.Error("重连失败", "attempt", attempt, "error", lastErr)
+	}
+}
+
+// giveUp 放弃重连，等待重发的动作不会再有机会发送，立即以 ErrConnectionLost 结束
+func (c *Client) giveUp(attempts int, err error) {
+	c.setState(StateGaveUp, attempts, err)
+	c.failHeld()
+	if c.onGiveUp != nil {
+		c.onGiveUp(attempts, err)
 	}
 }
 
@@ -326,10 +523,16 @@ func (c *Client) Close() error {
 	c.cancel()
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	conn := c.conn
+	server := c.server
+	c.mu.Unlock()
 
-	if c.conn != nil {
-		return c.conn.Close(websocket.StatusNormalClosure, "客户端关闭")
+	if server != nil {
+		server.Close()
+	}
+
+	if conn != nil {
+		return conn.Close(websocket.StatusNormalClosure, "客户端关闭")
 	}
 
 	return nil
@@ -341,49 +544,205 @@ func (c *Client) IsConnected() bool {
 }
 
 // On 注册事件处理器
-// eventType 可以是 "*"（所有事件）、"message"（所有消息）、"message.private"（私聊消息）等
-func (c *Client) On(eventType string, handler EventHandler) {
-	c.handlerMu.Lock()
-	defer c.handlerMu.Unlock()
+// eventType 可以是 "*"（所有事件）、"message"（所有消息）、"message.private"（私聊消息）、
+// "notice.group_member_increase.invite"（带子类型）等，事件会分发给所有匹配的处理器
+// 返回的订阅句柄可用于注销处理器
+func (c *Client) On(eventType string, handler EventHandler) *Subscription {
+	return c.HandleFunc(eventType, func(event any) error {
+		handler(event)
+		return nil
+	})
+}
 
-	c.eventHandlers[eventType] = append(c.eventHandlers[eventType], handler)
+// HandleFunc 注册返回错误的事件处理器，eventType 的含义与 On 相同
+// 处理器返回的错误和 panic 都会记录日志并交给 WithErrorHandler 设置的错误处理函数
+func (c *Client) HandleFunc(eventType string, handler HandlerFunc) *Subscription {
+	sub := &Subscription{}
+	c.register(eventType, sub, handler)
+	return sub
 }
 
-// Call 调用动作
+// Call 调用动作（使用默认超时时间）
 func (c *Client) Call(action string, params map[string]any) (*ActionResponse, error) {
 	return c.CallWithTimeout(action, params, c.timeout)
 }
 
 // CallWithTimeout 调用动作（带超时）
 func (c *Client) CallWithTimeout(action string, params map[string]any, timeout time.Duration) (*ActionResponse, error) {
-	if !c.IsConnected() {
-		return nil, fmt.Errorf("未连接到 OneBot 实现")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return c.CallContext(ctx, action, params)
+}
+
+// CallContext 调用动作（带上下文）
+// ctx 被取消时立即放弃等待并返回 ctx.Err()，超时时返回的错误同时匹配 ErrTimeout 和 context.DeadlineExceeded；
+// ctx 未设置截止时间时使用默认超时时间
+func (c *Client) CallContext(ctx context.Context, action string, params map[string]any) (*ActionResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
 	}
 
 	request := NewActionRequest(action, params)
-	if c.self != nil {
+	if self, ok := selfFromContext(ctx); ok {
+		request.WithSelf(&self)
+	} else if c.self != nil {
 		request.WithSelf(c.self)
 	}
 
+	c.interceptorMu.RLock()
+	interceptors := c.interceptors
+	c.interceptorMu.RUnlock()
+
+	return chainActions(c.sendAction, interceptors)(ctx, request)
+}
+
+// sendAction 通过当前通信方式发送动作请求，位于动作拦截器链的末端
+func (c *Client) sendAction(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+	// HTTP 通信方式无需长连接
+	if c.isHTTP() {
+		return c.callHTTP(ctx, request)
+	}
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	return c.callWebSocket(ctx, request)
+}
+
+// callWebSocket 通过 WebSocket 连接调用动作
+func (c *Client) callWebSocket(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+	// 每次发送使用独立的请求副本，拦截器重试时不会复用上一次的 echo
+	clone := *request
+	request = &clone
+	if request.Echo == "" {
+		request.Echo = uuid.New().String()
+	}
+
 	call := &actionCall{
 		request:  request,
 		response: make(chan *ActionResponse, 1),
+		err:      make(chan error, 1),
 	}
 
+	// 注册响应通道
+	c.responseMu.Lock()
+	c.responseChan[request.Echo] = call
+	c.responseMu.Unlock()
+
 	select {
 	case c.actionChan <- call:
-	case <-time.After(timeout):
-		return nil, fmt.Errorf("发送动作请求超时")
+	case <-ctx.Done():
+		c.removeResponseChan(request.Echo)
+		return nil, contextError(ctx.Err())
 	}
 
 	select {
 	case response := <-call.response:
 		return response, nil
-	case <-time.After(timeout):
-		// 清理响应通道
-		c.responseMu.Lock()
-		delete(c.responseChan, request.Echo)
-		c.responseMu.Unlock()
-		return nil, fmt.Errorf("等待动作响应超时")
+	case err := <-call.err:
+		return nil, err
+	case <-c.ctx.Done():
+		c.removeResponseChan(request.Echo)
+		return nil, ErrConnectionLost
+	case <-ctx.Done():
+		c.removeResponseChan(request.Echo)
+		return nil, contextError(ctx.Err())
+	}
+}
+
+// removeResponseChan 清理响应通道
+func (c *Client) removeResponseChan(echo string) {
+	c.responseMu.Lock()
+	delete(c.responseChan, echo)
+	c.responseMu.Unlock()
+}
+
+// registered 判断动作调用是否仍在等待响应
+func (c *Client) registered(echo string) bool {
+	c.responseMu.RLock()
+	defer c.responseMu.RUnlock()
+
+	_, ok := c.responseChan[echo]
+	return ok
+}
+
+// failPending 连接断开时结束所有已发送但未收到响应的动作调用
+// 可重发的动作保留到重连后重新发送，其余立即返回 ErrConnectionLost
+func (c *Client) failPending() {
+	var sent []*actionCall
+
+	c.responseMu.RLock()
+	for _, call := range c.responseChan {
+		// 尚未发送的动作由 writeLoop 处理
+		if call.sent.CompareAndSwap(true, false) {
+			sent = append(sent, call)
+		}
+	}
+	c.responseMu.RUnlock()
+
+	for _, call := range sent {
+		c.holdOrFail(call)
+	}
+}
+
+// holdOrFail 保留可重发的动作调用，其余以 ErrConnectionLost 结束
+// 不会重连（未启用自动重连或已放弃重连）时可重发的动作同样立即结束
+func (c *Client) holdOrFail(call *actionCall) {
+	if c.resendActions[call.request.Action] && !c.closed.Load() {
+		// 在 heldMu 内判断状态，保证 giveUp 之后不会再保留新的调用
+		c.heldMu.Lock()
+		held := c.reverse.Load() || (c.reconnect && c.State() != StateGaveUp)
+		if held {
+			// 保持注册，重连后重发时仍能收到响应；调用方放弃等待后 resendHeld 会跳过该调用
+			c.held = append(c.held, call)
+		}
+		c.heldMu.Unlock()
+
+		if held {
+			c.logger.Debug("连接断开，动作将在重连后重发", "action", call.request.Action)
+			return
+		}
+	}
+
+	c.removeResponseChan(call.request.Echo)
+	call.fail(ErrConnectionLost)
+}
+
+// failHeld 以 ErrConnectionLost 结束所有等待重发的动作调用
+func (c *Client) failHeld() {
+	c.heldMu.Lock()
+	held := c.held
+	c.held = nil
+	c.heldMu.Unlock()
+
+	for _, call := range held {
+		c.removeResponseChan(call.request.Echo)
+		call.fail(ErrConnectionLost)
+	}
+}
+
+// resendHeld 重连后重新发送保留的动作调用
+func (c *Client) resendHeld() {
+	c.heldMu.Lock()
+	held := c.held
+	c.held = nil
+	c.heldMu.Unlock()
+
+	for _, call := range held {
+		// 调用方已放弃等待的动作不再重发
+		if !c.registered(call.request.Echo) {
+			continue
+		}
+
+		c.logger.Info("重发动作请求", "action", call.request.Action)
+		select {
+		case c.actionChan <- call:
+		case <-c.ctx.Done():
+			return
+		}
 	}
 }
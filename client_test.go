@@ -1,6 +1,11 @@
 package onebot
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -247,3 +252,934 @@ func TestTimestamp(t *testing.T) {
 		t.Errorf("Timestamp() 返回的时间戳不正确: %f (diff: %f)", ts, diff)
 	}
 }
+
+func TestCallContextCancel(t *testing.T) {
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+
+	// 模拟已连接但实现不响应的情况
+	client.connected.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := client.CallContext(ctx, "get_version", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("应返回 context.Canceled: got %v", err)
+	}
+
+	// 超时同时匹配 ErrTimeout 和 context.DeadlineExceeded
+	_, err = client.CallWithTimeout("get_version", nil, 10*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("应返回 ErrTimeout: got %v", err)
+	}
+
+	client.responseMu.RLock()
+	pending := len(client.responseChan)
+	client.responseMu.RUnlock()
+	if pending != 0 {
+		t.Errorf("取消后响应通道未清理: %d", pending)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff{Initial: time.Second, Max: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := backoff.Delay(i + 1); got != want {
+			t.Errorf("第 %d 次重连等待时间错误: got %v, want %v", i+1, got, want)
+		}
+	}
+
+	for range 100 {
+		d := applyJitter(time.Second, 0.2)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("抖动超出范围: %v", d)
+		}
+	}
+}
+
+func TestReconnectGiveUp(t *testing.T) {
+	gaveUp := make(chan int, 1)
+	client, _ := New("ws://127.0.0.1:1",
+		WithExponentialBackoff(time.Millisecond, 10*time.Millisecond),
+		WithMaxReconnectAttempts(3),
+		WithGiveUp(func(attempts int, err error) {
+			if err == nil {
+				t.Error("放弃重连时应携带最后一次错误")
+			}
+			gaveUp <- attempts
+		}),
+	)
+	defer client.Close()
+
+	go client.reconnectLoop()
+
+	select {
+	case attempts := <-gaveUp:
+		if attempts != 3 {
+			t.Errorf("重连次数错误: got %d, want %d", attempts, 3)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("未放弃重连")
+	}
+}
+
+func TestBotRegistry(t *testing.T) {
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+
+	changes := make(chan BotInfo, 10)
+	client.OnBotStatus(func(bot BotInfo) {
+		changes <- bot
+	})
+
+	bot1 := Self{Platform: "qq", UserID: "1"}
+	bot2 := Self{Platform: "qq", UserID: "2"}
+
+	client.updateBots([]BotInfo{{Self: bot1, Online: true}, {Self: bot2, Online: true}})
+	if !client.IsOnline(bot1) || !client.IsOnline(bot2) {
+		t.Fatal("机器人应在线")
+	}
+	if len(changes) != 2 {
+		t.Fatalf("状态变化通知数量错误: got %d, want %d", len(changes), 2)
+	}
+	<-changes
+	<-changes
+
+	// meta.status_update 事件更新状态
+	event, _ := ParseEvent([]byte(`{"id":"1","time":1,"type":"meta","detail_type":"status_update","sub_type":"","status":{"good":true,"bots":[{"self":{"platform":"qq","user_id":"1"},"online":false}]}}`))
+	client.handleEvent(event)
+
+	if client.IsOnline(bot1) {
+		t.Error("bot1 应已下线")
+	}
+	if client.IsOnline(bot2) {
+		t.Error("bot2 不在状态列表中，应视为下线")
+	}
+	if bots := client.Bots(); len(bots) != 1 || bots[0].Self != bot1 {
+		t.Errorf("Bots() 错误: %+v", bots)
+	}
+	if len(changes) != 2 {
+		t.Errorf("状态变化通知数量错误: got %d, want %d", len(changes), 2)
+	}
+}
+
+// groupMessage 构造群消息事件
+func groupMessage(id, groupID string) *MessageEvent {
+	event, _ := ParseEvent([]byte(`{"id":"` + id + `","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"message","detail_type":"group","sub_type":"","message_id":"` + id + `","message":"hi","alt_message":"hi","user_id":"u1","group_id":"` + groupID + `"}`))
+	return event.(*MessageEvent)
+}
+
+func TestEventRouting(t *testing.T) {
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+
+	received := make(chan string, 10)
+	for _, key := range []string{"*", "message", "message.group", "message.private", "notice"} {
+		client.On(key, func(event any) {
+			received <- key
+		})
+	}
+
+	client.handleEvent(groupMessage("1", "g1"))
+
+	got := map[string]bool{}
+	for range 3 {
+		select {
+		case key := <-received:
+			got[key] = true
+		case <-time.After(time.Second):
+			t.Fatalf("处理器未被调用: %v", got)
+		}
+	}
+	for _, key := range []string{"*", "message", "message.group"} {
+		if !got[key] {
+			t.Errorf("%s 处理器未被调用", key)
+		}
+	}
+
+	select {
+	case key := <-received:
+		t.Errorf("不应调用 %s 处理器", key)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcherOrder(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithWorkers(4), WithOverflowPolicy(OverflowBlock))
+	defer client.Close()
+
+	const count = 200
+	var mu sync.Mutex
+	got := map[string][]string{}
+	done := make(chan struct{}, count*2)
+	client.On("message.group", func(event any) {
+		msg := event.(*MessageEvent)
+		mu.Lock()
+		got[msg.GroupID] = append(got[msg.GroupID], msg.ID)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	for i := range count {
+		client.handleEvent(groupMessage(strconv.Itoa(i), "g1"))
+		client.handleEvent(groupMessage(strconv.Itoa(i), "g2"))
+	}
+
+	for range count * 2 {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("事件未处理完成")
+		}
+	}
+
+	for _, group := range []string{"g1", "g2"} {
+		for i, id := range got[group] {
+			if id != strconv.Itoa(i) {
+				t.Fatalf("%s 的事件顺序错误: 第 %d 个为 %s", group, i, id)
+			}
+		}
+	}
+}
+
+func TestDispatcherOverflow(t *testing.T) {
+	client, _ := New("ws://localhost:5700",
+		WithWorkers(1),
+		WithQueueSize(1),
+		WithOverflowPolicy(OverflowDropNewest),
+	)
+	defer client.Close()
+
+	release := make(chan struct{})
+	var handled atomic.Int32
+	client.On("message", func(event any) {
+		<-release
+		handled.Add(1)
+	})
+
+	// 第一个事件占用工作协程，第二个进入队列，其余被丢弃
+	client.handleEvent(groupMessage("1", "g1"))
+	time.Sleep(50 * time.Millisecond)
+	for i := 2; i <= 5; i++ {
+		client.handleEvent(groupMessage(strconv.Itoa(i), "g1"))
+	}
+	close(release)
+
+	time.Sleep(100 * time.Millisecond)
+	if n := handled.Load(); n != 2 {
+		t.Errorf("处理的事件数量错误: got %d, want %d", n, 2)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	errs := make(chan error, 2)
+	client, _ := New("ws://localhost:5700", WithErrorHandler(func(event any, err error) {
+		errs <- err
+	}))
+	defer client.Close()
+
+	errFailed := errors.New("处理失败")
+	client.On("message", func(event any) {
+		panic("boom")
+	})
+	client.HandleFunc("message", func(event any) error {
+		return errFailed
+	})
+
+	client.handleEvent(groupMessage("1", "g1"))
+
+	var gotPanic, gotErr bool
+	for range 2 {
+		select {
+		case err := <-errs:
+			var panicErr *PanicError
+			switch {
+			case errors.As(err, &panicErr):
+				gotPanic = panicErr.Value == "boom" && len(panicErr.Stack) > 0
+			case errors.Is(err, errFailed):
+				gotErr = true
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("未收到处理器错误")
+		}
+	}
+	if !gotPanic || !gotErr {
+		t.Errorf("错误处理结果错误: panic=%v, error=%v", gotPanic, gotErr)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithWorkers(1))
+	defer client.Close()
+
+	var mu sync.Mutex
+	var trace []string
+	record := func(s string) {
+		mu.Lock()
+		trace = append(trace, s)
+		mu.Unlock()
+	}
+
+	// 全局中间件：丢弃 g2 的事件，并改写消息内容
+	client.Use(func(next HandlerFunc) HandlerFunc {
+		return func(event any) error {
+			msg := event.(*MessageEvent)
+			if msg.GroupID == "g2" {
+				return nil
+			}
+			msg.AltMessage = "enriched"
+			return next(msg)
+		}
+	})
+	client.UseOn("message.group", func(next HandlerFunc) HandlerFunc {
+		return func(event any) error {
+			record("group-mw")
+			return next(event)
+		}
+	})
+
+	done := make(chan struct{}, 2)
+	client.On("message", func(event any) {
+		record("message:" + event.(*MessageEvent).AltMessage)
+		done <- struct{}{}
+	})
+	client.On("message.group", func(event any) {
+		record("group")
+		done <- struct{}{}
+	})
+
+	client.handleEvent(groupMessage("1", "g2"))
+	client.handleEvent(groupMessage("2", "g1"))
+
+	for range 2 {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("事件未处理完成")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"message:enriched", "group-mw", "group"}
+	if len(trace) != len(want) {
+		t.Fatalf("执行顺序错误: got %v, want %v", trace, want)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("执行顺序错误: got %v, want %v", trace, want)
+		}
+	}
+}
+
+func TestActionInterceptors(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithSelf("qq", "bot"))
+	defer client.Close()
+
+	// 审计：记录所有动作请求
+	var audit []string
+	client.UseAction(func(next ActionInvoker) ActionInvoker {
+		return func(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+			if request.Self == nil || request.Self.UserID != "bot" {
+				t.Errorf("请求未填充 self: %+v", request.Self)
+			}
+			audit = append(audit, request.Action)
+			return next(ctx, request)
+		}
+	})
+	// 缓存：直接返回 get_self_info 的响应
+	client.UseAction(func(next ActionInvoker) ActionInvoker {
+		return func(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+			if request.Action == "get_self_info" {
+				return &ActionResponse{
+					Status: "ok",
+					Data:   map[string]any{"user_id": "bot", "user_name": "cached"},
+				}, nil
+			}
+			return next(ctx, request)
+		}
+	})
+
+	info, err := client.GetSelfInfo()
+	if err != nil {
+		t.Fatalf("获取自身信息失败: %v", err)
+	}
+	if info.UserName != "cached" {
+		t.Errorf("UserName 错误: got %s, want %s", info.UserName, "cached")
+	}
+
+	// 未被拦截的动作继续发送，未连接时返回 ErrNotConnected
+	if _, err := client.SendPrivateMessage("u1", Message{Text("hi")}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("应返回 ErrNotConnected: got %v", err)
+	}
+
+	if len(audit) != 2 || audit[0] != "get_self_info" || audit[1] != "send_message" {
+		t.Errorf("审计记录错误: %v", audit)
+	}
+}
+
+func TestTypedHandlers(t *testing.T) {
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+
+	messages := make(chan *MessageEvent, 2)
+	client.OnMessage(func(msg *MessageEvent) {
+		messages <- msg
+	})
+	Handle(client, func(msg *MessageEvent) {
+		messages <- msg
+	})
+	notices := make(chan *NoticeEvent, 2)
+	client.OnNotice(func(notice *NoticeEvent) {
+		notices <- notice
+	})
+	// 标准通知解析为具体类型，Handle 的 *NoticeEvent 处理器同样能收到
+	Handle(client, func(notice *NoticeEvent) {
+		notices <- notice
+	})
+
+	client.handleEvent(groupMessage("1", "g1"))
+	for range 2 {
+		select {
+		case msg := <-messages:
+			if msg.GroupID != "g1" {
+				t.Errorf("GroupID 错误: got %s, want %s", msg.GroupID, "g1")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("未收到消息事件")
+		}
+	}
+
+	select {
+	case <-notices:
+		t.Error("通知处理器不应收到消息事件")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	event, _ := ParseEvent([]byte(`{"id":"n1","time":1,"type":"notice","detail_type":"friend_increase","sub_type":"","user_id":"u1"}`))
+	client.handleEvent(event)
+	for range 2 {
+		select {
+		case notice := <-notices:
+			if notice.DetailType != "friend_increase" {
+				t.Errorf("DetailType 错误: got %s, want %s", notice.DetailType, "friend_increase")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("未收到通知事件")
+		}
+	}
+}
+
+func TestUnsubscribeAndOnce(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithWorkers(1))
+	defer client.Close()
+
+	var always, once atomic.Int32
+	sub := client.On("message", func(event any) {
+		always.Add(1)
+	})
+	client.Once("message", func(event any) {
+		once.Add(1)
+	})
+	done := make(chan struct{}, 10)
+	client.On("*", func(event any) {
+		done <- struct{}{}
+	})
+
+	wait := func() {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("事件未处理完成")
+		}
+	}
+
+	client.handleEvent(groupMessage("1", "g1"))
+	client.handleEvent(groupMessage("2", "g1"))
+	wait()
+	wait()
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	client.handleEvent(groupMessage("3", "g1"))
+	wait()
+
+	if n := always.Load(); n != 2 {
+		t.Errorf("注销后处理器仍被调用: got %d, want %d", n, 2)
+	}
+	if n := once.Load(); n != 1 {
+		t.Errorf("Once 处理器调用次数错误: got %d, want %d", n, 1)
+	}
+	if _, ok := client.eventHandlers["message"]; ok {
+		t.Error("所有处理器注销后应删除对应的键")
+	}
+}
+
+func TestWaitForAndSubscribe(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithWorkers(1))
+	defer client.Close()
+
+	events, sub := client.Subscribe(func(event any) bool {
+		msg, ok := event.(*MessageEvent)
+		return ok && msg.GroupID == "g1"
+	})
+
+	// 在处理器中等待同一用户的回复，工作池不应因此阻塞
+	replies := make(chan any, 1)
+	client.Once("message.group", func(event any) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		reply, err := client.WaitFor(ctx, SameSender(event.(*MessageEvent)))
+		if err != nil {
+			t.Errorf("等待回复失败: %v", err)
+		}
+		replies <- reply
+	})
+
+	client.handleEvent(groupMessage("1", "g1"))
+	time.Sleep(50 * time.Millisecond)
+	client.handleEvent(groupMessage("2", "g2"))
+	client.handleEvent(groupMessage("3", "g1"))
+
+	select {
+	case reply := <-replies:
+		if msg := reply.(*MessageEvent); msg.ID != "3" {
+			t.Errorf("回复错误: got %s, want %s", msg.ID, "3")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("未等到回复")
+	}
+
+	for _, want := range []string{"1", "3"} {
+		if msg := (<-events).(*MessageEvent); msg.ID != want {
+			t.Errorf("订阅的事件错误: got %s, want %s", msg.ID, want)
+		}
+	}
+	sub.Unsubscribe()
+	if _, ok := <-events; ok {
+		t.Error("注销后通道应关闭")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitFor(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("应返回 context.DeadlineExceeded: got %v", err)
+	}
+}
+
+func TestSession(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithCancelKeywords("取消"))
+	defer client.Close()
+
+	handled := make(chan string, 10)
+	client.OnMessage(func(msg *MessageEvent) {
+		handled <- msg.ID
+	})
+
+	first := groupMessage("1", "g1")
+	session, err := client.StartSession(first)
+	if err != nil {
+		t.Fatalf("开始会话失败: %v", err)
+	}
+	if _, err := client.StartSession(first); !errors.Is(err, ErrSessionActive) {
+		t.Errorf("应返回 ErrSessionActive: got %v", err)
+	}
+	session.Set("step", 1)
+
+	// 同一用户的消息交给会话，其他用户的消息正常分发
+	other := groupMessage("2", "g1")
+	other.UserID = "u2"
+	client.handleEvent(other)
+	client.handleEvent(groupMessage("3", "g1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := session.Next(ctx)
+	if err != nil {
+		t.Fatalf("等待会话消息失败: %v", err)
+	}
+	if msg.ID != "3" || session.Get("step") != 1 {
+		t.Errorf("会话消息或状态错误: id=%s, step=%v", msg.ID, session.Get("step"))
+	}
+	select {
+	case id := <-handled:
+		if id != "2" {
+			t.Errorf("处理器收到会话消息: %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("其他用户的消息未分发给处理器")
+	}
+
+	// 取消关键词结束会话
+	cancelMsg := groupMessage("4", "g1")
+	cancelMsg.AltMessage = "取消"
+	client.handleEvent(cancelMsg)
+	if _, err := session.Next(ctx); !errors.Is(err, ErrSessionCanceled) {
+		t.Errorf("应返回 ErrSessionCanceled: got %v", err)
+	}
+	if client.Session(first) != nil {
+		t.Error("会话取消后应被移除")
+	}
+
+	// 超时结束会话
+	timed, _ := client.StartSession(first)
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := timed.Next(short); !errors.Is(err, ErrTimeout) {
+		t.Errorf("应返回 ErrTimeout: got %v", err)
+	}
+	client.handleEvent(groupMessage("5", "g1"))
+	select {
+	case id := <-handled:
+		if id != "5" {
+			t.Errorf("处理器收到的消息错误: got %s, want %s", id, "5")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("会话结束后消息未恢复分发")
+	}
+}
+
+func TestDedup(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithDedup(2, time.Minute), WithWorkers(1))
+	defer client.Close()
+
+	var handled atomic.Int32
+	done := make(chan struct{}, 10)
+	client.On("message", func(event any) {
+		handled.Add(1)
+		done <- struct{}{}
+	})
+
+	// 1 重复；容量为 2，记录 3 后 1 被淘汰，再次到达时视为新事件
+	for _, id := range []string{"1", "1", "2", "2", "3", "1"} {
+		client.handleEvent(groupMessage(id, "g1"))
+	}
+	for range 4 {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("事件未处理完成")
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := handled.Load(); n != 4 {
+		t.Errorf("处理的事件数量错误: got %d, want %d", n, 4)
+	}
+
+	store := NewMemoryDedupStore(10)
+	store.Add("x", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if added, _ := store.Add("x", time.Minute); !added {
+		t.Error("过期的事件 ID 应可再次记录")
+	}
+}
+
+func TestEventExtensions(t *testing.T) {
+	data := `{"id":"1","time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"hi","alt_message":"hi","user_id":"u1","wx.avatar":"a.png","wx_nickname":"nick","qq.level":3}`
+	event, err := ParseEvent([]byte(data))
+	if err != nil {
+		t.Fatalf("解析事件失败: %v", err)
+	}
+	msg := event.(*MessageEvent)
+
+	if string(msg.Raw) != data {
+		t.Errorf("原始 JSON 错误: %s", msg.Raw)
+	}
+	if len(msg.Extra) != 3 {
+		t.Errorf("扩展字段数量错误: %v", msg.Extra)
+	}
+	if v, ok := msg.Extension("qq.level"); !ok || v != float64(3) {
+		t.Errorf("Extension 错误: %v", v)
+	}
+
+	wx := msg.Extensions("wx")
+	if len(wx) != 2 || wx["avatar"] != "a.png" || wx["nickname"] != "nick" {
+		t.Errorf("Extensions 错误: %v", wx)
+	}
+
+	var ext struct {
+		Avatar string `json:"wx.avatar"`
+	}
+	if err := msg.Unmarshal(&ext); err != nil || ext.Avatar != "a.png" {
+		t.Errorf("Unmarshal 错误: %+v, %v", ext, err)
+	}
+}
+
+type testPatNotice struct {
+	NoticeEvent
+	TargetID string `json:"wx.target_id"`
+}
+
+func TestRegisterEvent(t *testing.T) {
+	RegisterEvent[testPatNotice]("notice.wx.pat")
+
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+
+	typed := make(chan *testPatNotice, 1)
+	Handle(client, func(notice *testPatNotice) {
+		typed <- notice
+	})
+	keyed := make(chan any, 1)
+	client.On("notice.wx.pat", func(event any) {
+		keyed <- event
+	})
+
+	event, err := ParseEvent([]byte(`{"id":"1","time":1,"type":"notice","detail_type":"wx.pat","sub_type":"","user_id":"u1","wx.target_id":"u2"}`))
+	if err != nil {
+		t.Fatalf("解析事件失败: %v", err)
+	}
+	client.handleEvent(event)
+
+	select {
+	case notice := <-typed:
+		if notice.TargetID != "u2" || notice.UserID != "u1" {
+			t.Errorf("扩展事件字段错误: %+v", notice)
+		}
+		if len(notice.Extra) != 0 {
+			t.Errorf("已声明的字段不应出现在 Extra 中: %v", notice.Extra)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到扩展事件")
+	}
+
+	select {
+	case <-keyed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("按键注册的处理器未收到扩展事件")
+	}
+}
+
+func TestStandardNotices(t *testing.T) {
+	parse := func(detailType, extra string) any {
+		event, err := ParseEvent([]byte(`{"id":"1","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"notice","detail_type":"` + detailType + `","sub_type":"","user_id":"u1","operator_id":"u2"` + extra + `}`))
+		if err != nil {
+			t.Fatalf("解析 %s 失败: %v", detailType, err)
+		}
+		return event
+	}
+
+	if _, ok := parse("friend_increase", "").(*FriendIncreaseNotice); !ok {
+		t.Error("friend_increase 应解析为 *FriendIncreaseNotice")
+	}
+	if n, ok := parse("group_message_delete", `,"group_id":"g1","message_id":"m1"`).(*GroupMessageDeleteNotice); !ok || n.GroupID != "g1" || n.MessageID != "m1" {
+		t.Errorf("group_message_delete 解析错误: %+v", n)
+	}
+
+	event := parse("channel_message_delete", `,"guild_id":"gd","channel_id":"ch","message_id":"m1"`)
+	n, ok := event.(*ChannelMessageDeleteNotice)
+	if !ok || n.GuildID != "gd" || n.ChannelID != "ch" || n.MessageID != "m1" || n.OperatorID != "u2" {
+		t.Fatalf("channel_message_delete 解析错误: %+v", event)
+	}
+	if len(n.Extra) != 0 {
+		t.Errorf("标准字段不应出现在 Extra 中: %v", n.Extra)
+	}
+	if key := ConversationKey(event); key != "qq:bot:channel:gd/ch" {
+		t.Errorf("ConversationKey 错误: %s", key)
+	}
+
+	// OnNotice 接收所有标准通知
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+	notices := make(chan *NoticeEvent, 2)
+	client.OnNotice(func(notice *NoticeEvent) {
+		notices <- notice
+	})
+	// 标准通知解析为具体类型，Handle 的 *NoticeEvent 处理器同样能收到
+	Handle(client, func(notice *NoticeEvent) {
+		notices <- notice
+	})
+	client.handleEvent(event)
+	select {
+	case notice := <-notices:
+		if notice.DetailType != "channel_message_delete" {
+			t.Errorf("DetailType 错误: %s", notice.DetailType)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnNotice 未收到通知")
+	}
+}
+
+func TestActionRetryEcho(t *testing.T) {
+	client, _ := New("ws://localhost:5700")
+	defer client.Close()
+	client.connected.Store(true)
+
+	// 拦截器重试时每次发送都应使用新的 echo
+	client.UseAction(func(next ActionInvoker) ActionInvoker {
+		return func(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+			for range 2 {
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				next(ctx, request)
+				cancel()
+			}
+			if request.Echo != "" {
+				t.Errorf("拦截器共享的请求不应被写入 echo: %s", request.Echo)
+			}
+			return nil, ErrTimeout
+		}
+	})
+
+	echoes := make(chan string, 2)
+	go func() {
+		for call := range client.actionChan {
+			echoes <- call.request.Echo
+		}
+	}()
+	client.Call("get_self_info", nil)
+
+	first, second := <-echoes, <-echoes
+	if first == "" || first == second {
+		t.Errorf("重试应使用新的 echo: %s, %s", first, second)
+	}
+}
+
+func TestSubscribeFilterPanic(t *testing.T) {
+	errs := make(chan error, 1)
+	client, _ := New("ws://localhost:5700", WithErrorHandler(func(event any, err error) {
+		errs <- err
+	}))
+	defer client.Close()
+
+	_, bad := client.Subscribe(func(event any) bool {
+		panic("filter")
+	})
+	defer bad.Unsubscribe()
+	events, sub := client.Subscribe(nil)
+	defer sub.Unsubscribe()
+
+	client.handleEvent(groupMessage("1", "g1"))
+
+	var panicErr *PanicError
+	if err := <-errs; !errors.As(err, &panicErr) || panicErr.Value != "filter" {
+		t.Errorf("过滤函数的 panic 未报告: %v", err)
+	}
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("其他订阅者未收到事件")
+	}
+}
+
+func TestMiddlewareSeesHandlerErrors(t *testing.T) {
+	reported := make(chan error, 10)
+	client, _ := New("ws://localhost:5700", WithErrorHandler(func(event any, err error) {
+		reported <- err
+	}))
+	defer client.Close()
+
+	errFailed := errors.New("处理失败")
+	seen := make(chan error, 1)
+	client.Use(func(next HandlerFunc) HandlerFunc {
+		return func(event any) error {
+			err := next(event)
+			seen <- err
+			// 中间件处理了错误，不再上报
+			return nil
+		}
+	})
+
+	var ran atomic.Int32
+	client.HandleFunc("message", func(event any) error {
+		ran.Add(1)
+		return errFailed
+	})
+	client.On("message.group", func(event any) {
+		ran.Add(1)
+		panic("boom")
+	})
+
+	client.handleEvent(groupMessage("1", "g1"))
+
+	select {
+	case err := <-seen:
+		var panicErr *PanicError
+		if !errors.Is(err, errFailed) || !errors.As(err, &panicErr) {
+			t.Errorf("中间件应收到处理器的错误: %v", err)
+		}
+		if n := ran.Load(); n != 2 {
+			t.Errorf("next 返回时处理器应已执行完毕: %d", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("中间件未收到处理结果")
+	}
+
+	select {
+	case err := <-reported:
+		t.Errorf("中间件已处理的错误不应上报: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcherBlockClose(t *testing.T) {
+	client, _ := New("ws://localhost:5700",
+		WithWorkers(1),
+		WithQueueSize(1),
+		WithOverflowPolicy(OverflowBlock),
+	)
+
+	release := make(chan struct{})
+	defer close(release)
+	client.On("message", func(event any) {
+		<-release
+	})
+
+	// 第一个事件占用工作协程，第二个进入队列，第三个阻塞等待
+	client.handleEvent(groupMessage("1", "g1"))
+	time.Sleep(50 * time.Millisecond)
+	client.handleEvent(groupMessage("2", "g1"))
+
+	done := make(chan struct{})
+	go func() {
+		client.handleEvent(groupMessage("3", "g1"))
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	client.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("客户端关闭后提交事件仍然阻塞")
+	}
+}
+
+func TestSessionIdleTimeout(t *testing.T) {
+	client, _ := New("ws://localhost:5700", WithSessionTimeout(50*time.Millisecond))
+	defer client.Close()
+
+	handled := make(chan string, 1)
+	client.OnMessage(func(msg *MessageEvent) {
+		handled <- msg.ID
+	})
+
+	// 开始会话后不调用 Next，会话应在空闲超时后自动结束
+	first := groupMessage("1", "g1")
+	session, _ := client.StartSession(first)
+
+	select {
+	case <-session.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("空闲会话未自动结束")
+	}
+	if client.Session(first) != nil {
+		t.Error("空闲超时后会话应被移除")
+	}
+
+	client.handleEvent(groupMessage("2", "g1"))
+	select {
+	case id := <-handled:
+		if id != "2" {
+			t.Errorf("处理器收到的消息错误: got %s, want %s", id, "2")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("会话结束后消息未恢复分发")
+	}
+}
@@ -0,0 +1,91 @@
+package onebot
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DedupStore 事件去重存储
+// 多个进程共享同一个存储（如 Redis）即可跨进程去重
+type DedupStore interface {
+	// Add 记录事件 ID 并保留 ttl，ID 已存在且未过期时返回 false
+	Add(id string, ttl time.Duration) (bool, error)
+}
+
+// MemoryDedupStore 基于内存的去重存储，最多保留 size 个最近的事件 ID
+type MemoryDedupStore struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	mu      sync.Mutex
+}
+
+// dedupEntry 去重记录
+type dedupEntry struct {
+	id      string
+	expires time.Time
+}
+
+// NewMemoryDedupStore 创建基于内存的去重存储
+func NewMemoryDedupStore(size int) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		size:    max(size, 1),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Add 实现 DedupStore 接口
+func (s *MemoryDedupStore) Add(id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := s.entries[id]; ok {
+		if now.Before(elem.Value.(*dedupEntry).expires) {
+			return false, nil
+		}
+		s.remove(elem)
+	}
+
+	// 淘汰过期记录，超出容量时淘汰最早的记录
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		if s.order.Len() < s.size && now.Before(front.Value.(*dedupEntry).expires) {
+			break
+		}
+		s.remove(front)
+	}
+
+	s.entries[id] = s.order.PushBack(&dedupEntry{id: id, expires: now.Add(ttl)})
+	return true, nil
+}
+
+// remove 删除去重记录
+func (s *MemoryDedupStore) remove(elem *list.Element) {
+	delete(s.entries, elem.Value.(*dedupEntry).id)
+	s.order.Remove(elem)
+}
+
+// duplicate 判断事件是否已经处理过，没有 ID 的事件不去重，存储出错时照常分发
+func (c *Client) duplicate(event any) bool {
+	if c.dedup == nil {
+		return false
+	}
+
+	e, ok := event.(interface{ Base() *Event })
+	if !ok || e.Base().ID == "" {
+		return false
+	}
+
+	id := e.Base().ID
+	added, err := c.dedup.Add(id, c.dedupTTL)
+	if err != nil {
+		c.logger.Error("事件去重失败", "event_id", id, "error", err)
+		return false
+	}
+	if !added {
+		c.logger.Debug("丢弃重复事件", "event_id", id)
+	}
+	return !added
+}
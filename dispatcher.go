@@ -0,0 +1,201 @@
+package onebot
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// OverflowPolicy 工作池队列已满时的处理策略
+type OverflowPolicy int
+
+// 队列溢出策略常量
+const (
+	OverflowBlock      OverflowPolicy = iota // 阻塞等待队列空位（会暂停读取连接，包括动作响应）
+	OverflowDropNewest                       // 丢弃新到达的事件
+	OverflowDropOldest                       // 丢弃队列中最早的事件
+)
+
+// dispatcher 事件分发工作池
+// 每个工作协程拥有独立的队列，同一会话键的任务总是进入同一队列，从而保证顺序
+type dispatcher struct {
+	ctx    context.Context
+	queues []chan func()
+	policy OverflowPolicy
+	logger *slog.Logger
+	next   atomic.Uint64
+}
+
+// newDispatcher 创建事件分发工作池
+func newDispatcher(ctx context.Context, workers, queueSize int, policy OverflowPolicy, logger *slog.Logger) *dispatcher {
+	d := &dispatcher{
+		ctx:    ctx,
+		queues: make([]chan func(), workers),
+		policy: policy,
+		logger: logger,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan func(), max(queueSize, 1))
+	}
+	return d
+}
+
+// start 启动工作协程，客户端关闭后退出
+func (d *dispatcher) start() {
+	for _, queue := range d.queues {
+		go func() {
+			for {
+				select {
+				case <-d.ctx.Done():
+					return
+				case task := <-queue:
+					task()
+				}
+			}
+		}()
+	}
+}
+
+// submit 按会话键提交任务，key 为空时轮流分配给各工作协程
+func (d *dispatcher) submit(key string, task func()) {
+	var index uint64
+	if key == "" {
+		index = d.next.Add(1)
+	} else {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		index = h.Sum64()
+	}
+	queue := d.queues[index%uint64(len(d.queues))]
+
+	switch d.policy {
+	case OverflowDropNewest:
+		select {
+		case queue <- task:
+		default:
+			d.logger.Warn("事件队列已满，丢弃新事件", "key", key)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case queue <- task:
+				return
+			default:
+			}
+			select {
+			case <-queue:
+				d.logger.Warn("事件队列已满，丢弃最早的事件", "key", key)
+			default:
+			}
+		}
+	default:
+		// 客户端关闭后工作协程已退出，不再等待
+		select {
+		case queue <- task:
+		case <-d.ctx.Done():
+		}
+	}
+}
+
+// invoke 执行事件处理器，捕获 panic 并报告错误
+func (c *Client) invoke(handler HandlerFunc, event any) {
+	if err := safeCall(handler, event); err != nil {
+		c.reportError(event, err)
+	}
+}
+
+// safeCall 执行事件处理器，将 panic 转换为 *PanicError 返回
+func safeCall(handler HandlerFunc, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return handler(event)
+}
+
+// reportError 记录事件处理错误并交给错误处理函数，errors.Join 合并的错误逐个报告
+func (c *Client) reportError(event any, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			c.reportError(event, e)
+		}
+		return
+	}
+
+	var id string
+	if e, ok := event.(interface{ Base() *Event }); ok {
+		id = e.Base().ID
+	}
+	keys := eventKeys(event)
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		c.logger.Error("事件处理器 panic",
+			"event_id", id,
+			"event_type", keys[len(keys)-1],
+			"panic", panicErr.Value,
+			"stack", string(panicErr.Stack))
+	} else {
+		c.logger.Error("事件处理失败",
+			"event_id", id,
+			"event_type", keys[len(keys)-1],
+			"error", err)
+	}
+
+	if c.errorHandler != nil {
+		c.errorHandler(event, err)
+	}
+}
+
+// orderKey 返回事件的顺序键
+func (c *Client) orderKey(event any) string {
+	if c.orderKeyFunc != nil {
+		return c.orderKeyFunc(event)
+	}
+	return ConversationKey(event)
+}
+
+// ConversationKey 返回事件所属会话的键，形如 "platform:self_id:group:group_id"
+// 群消息按群、频道消息按频道、私聊消息按用户区分；无法归属会话的事件返回空字符串
+func ConversationKey(event any) string {
+	var base *Event
+	var userID, groupID, guildID, channelID string
+
+	switch e := event.(type) {
+	case *MessageEvent:
+		base, userID, groupID, guildID, channelID = &e.Event, e.UserID, e.GroupID, e.GuildID, e.ChannelID
+	case AnyNotice:
+		n := e.Notice()
+		base, userID, groupID = &n.Event, n.UserID, n.GroupID
+		if s, ok := event.(interface{ scope() (string, string) }); ok {
+			guildID, channelID = s.scope()
+		}
+	case *RequestEvent:
+		base, userID, groupID = &e.Event, e.UserID, e.GroupID
+	default:
+		return ""
+	}
+
+	prefix := ""
+	if base.Self != nil {
+		prefix = base.Self.Platform + ":" + base.Self.UserID + ":"
+	}
+
+	switch {
+	case channelID != "":
+		return prefix + "channel:" + guildID + "/" + channelID
+	case guildID != "":
+		return prefix + "guild:" + guildID
+	case groupID != "":
+		return prefix + "group:" + groupID
+	case userID != "":
+		return prefix + "private:" + userID
+	default:
+		return ""
+	}
+}
@@ -1,18 +1,34 @@
 package onebot
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
 // Error OneBot 错误
+// 动作执行失败时 Action 和 Echo 记录对应的动作请求，可配合 errors.Is / errors.As 按错误码判断
 type Error struct {
 	Code    int64  // 错误码
 	Message string // 错误信息
+	Action  string // 动作名称（动作失败时）
+	Echo    string // 动作请求的 echo（动作失败时）
 }
 
 // Error 实现 error 接口
 func (e *Error) Error() string {
+	if e.Action != "" {
+		return fmt.Sprintf("onebot error %d: %s (action: %s)", e.Code, e.Message, e.Action)
+	}
 	return fmt.Sprintf("onebot error %d: %s", e.Code, e.Message)
 }
 
+// Is 按错误码匹配，使 errors.Is(err, ErrUnsupportedAction) 对任意同错误码的错误成立
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
 // NewError 创建新错误
 func NewError(code int64, message string) *Error {
 	return &Error{
@@ -21,17 +37,66 @@ func NewError(code int64, message string) *Error {
 	}
 }
 
-// IsError 检查是否为特定错误码
+// newActionError 根据失败的动作响应创建错误
+func newActionError(action string, resp *ActionResponse) *Error {
+	return &Error{
+		Code:    resp.Retcode,
+		Message: resp.Message,
+		Action:  action,
+		Echo:    resp.Echo,
+	}
+}
+
+// IsError 检查错误链中是否包含特定错误码的 OneBot 错误
 func IsError(err error, code int64) bool {
-	if e, ok := err.(*Error); ok {
+	var e *Error
+	if errors.As(err, &e) {
 		return e.Code == code
 	}
 	return false
 }
 
+// contextError 转换上下文结束的错误，超时时返回同时匹配 ErrTimeout 和 context.DeadlineExceeded 的错误
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// PanicError 事件处理器发生 panic 时交给错误处理函数的错误
+type PanicError struct {
+	Value any    // panic 的值
+	Stack []byte // panic 时的调用栈
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("事件处理器 panic: %v", e.Value)
+}
+
 // 预定义错误
 var (
-	ErrNotConnected     = NewError(-1, "未连接到 OneBot 实现")
-	ErrTimeout          = NewError(-2, "操作超时")
-	ErrInvalidResponse  = NewError(-3, "无效的响应")
+	ErrNotConnected    = NewError(-1, "未连接到 OneBot 实现")
+	ErrTimeout         = NewError(-2, "操作超时")
+	ErrInvalidResponse = NewError(-3, "无效的响应")
+	ErrConnectionLost  = NewError(-4, "连接已断开")
+	ErrSessionActive   = NewError(-5, "会话已存在")
+	ErrSessionCanceled = NewError(-6, "会话已取消")
+	ErrVersionMismatch = NewError(-7, "不支持的 OneBot 版本")
+)
+
+// 返回码对应的错误，用于 errors.Is 判断动作失败原因
+var (
+	ErrBadRequest             = NewError(RetcodeBadRequest, "无效的动作请求")
+	ErrUnsupportedAction      = NewError(RetcodeUnsupportedAction, "不支持的动作")
+	ErrBadParam               = NewError(RetcodeBadParam, "无效的动作请求参数")
+	ErrUnsupportedParam       = NewError(RetcodeUnsupportedParam, "不支持的动作请求参数")
+	ErrUnsupportedSegment     = NewError(RetcodeUnsupportedSegment, "不支持的消息段类型")
+	ErrBadSegmentData         = NewError(RetcodeBadSegmentData, "无效的消息段参数")
+	ErrUnsupportedSegmentData = NewError(RetcodeUnsupportedSegmentData, "不支持的消息段参数")
+	ErrWhoAmI                 = NewError(RetcodeWhoAmI, "未指定机器人账号")
+	ErrUnknownSelf            = NewError(RetcodeUnknownSelf, "未知的机器人账号")
+	ErrBadHandler             = NewError(RetcodeBadHandler, "动作处理器实现错误")
+	ErrInternalHandlerError   = NewError(RetcodeInternalHandlerError, "动作处理器运行时异常")
 )
@@ -12,16 +12,27 @@ type Event struct {
 	Type       string  `json:"type"`        // 事件类型：meta/message/notice/request
 	DetailType string  `json:"detail_type"` // 事件详细类型
 	SubType    string  `json:"sub_type"`    // 事件子类型
+
+	Raw   json.RawMessage `json:"-"` // 事件的原始 JSON
+	Extra map[string]any  `json:"-"` // 标准结构之外的字段（如 "wx.xxx" 扩展字段）
+}
+
+// Base 返回事件的基础结构
+// 嵌入 Event 的事件类型都会获得该方法，可用于统一读取 ID、Self、Type 等公共字段
+func (e *Event) Base() *Event {
+	return e
 }
 
 // MessageEvent 消息事件
 type MessageEvent struct {
 	Event
-	MessageID  string  `json:"message_id"`         // 消息唯一 ID
-	Message    Message `json:"message"`            // 消息内容
-	AltMessage string  `json:"alt_message"`        // 消息内容的替代表示
-	UserID     string  `json:"user_id"`            // 用户 ID
-	GroupID    string  `json:"group_id,omitempty"` // 群 ID（群消息才有）
+	MessageID  string  `json:"message_id"`           // 消息唯一 ID
+	Message    Message `json:"message"`              // 消息内容
+	AltMessage string  `json:"alt_message"`          // 消息内容的替代表示
+	UserID     string  `json:"user_id"`              // 用户 ID
+	GroupID    string  `json:"group_id,omitempty"`   // 群 ID（群消息才有）
+	GuildID    string  `json:"guild_id,omitempty"`   // 群组 ID（频道消息才有）
+	ChannelID  string  `json:"channel_id,omitempty"` // 频道 ID（频道消息才有）
 }
 
 // NoticeEvent 通知事件
@@ -35,17 +46,21 @@ type NoticeEvent struct {
 // MetaEvent 元事件
 type MetaEvent struct {
 	Event
-	Interval int64      `json:"interval,omitempty"` // 心跳间隔（毫秒）
-	Status   *BotStatus `json:"status,omitempty"`   // 状态信息
+	Interval int64               `json:"interval,omitempty"` // 心跳间隔（毫秒，meta.heartbeat）
+	Status   *BotStatus          `json:"status,omitempty"`   // 状态信息（meta.status_update）
+	Version  *GetVersionResponse `json:"version,omitempty"`  // 实现版本信息（meta.connect）
 }
 
 // BotStatus 机器人状态
 type BotStatus struct {
-	Good bool `json:"good"` // 是否各项状态都符合预期
-	Bots []struct {
-		Self   Self `json:"self"`   // 机器人自身标识
-		Online bool `json:"online"` // 是否在线
-	} `json:"bots"` // 机器人账号状态列表
+	Good bool      `json:"good"` // 是否各项状态都符合预期
+	Bots []BotInfo `json:"bots"` // 机器人账号状态列表
+}
+
+// BotInfo 机器人账号状态
+type BotInfo struct {
+	Self   Self `json:"self"`   // 机器人自身标识
+	Online bool `json:"online"` // 是否在线
 }
 
 // RequestEvent 请求事件
@@ -57,41 +72,40 @@ type RequestEvent struct {
 }
 
 // ParseEvent 解析事件 JSON
+// 返回的事件保留原始 JSON（Event.Raw），标准结构之外的字段保存在 Event.Extra 中
 func ParseEvent(data []byte) (any, error) {
 	var base Event
 	if err := json.Unmarshal(data, &base); err != nil {
 		return nil, err
 	}
 
-	switch base.Type {
-	case "message":
-		var event MessageEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return nil, err
-		}
-		return &event, nil
-	case "notice":
-		var event NoticeEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return nil, err
-		}
-		return &event, nil
-	case "meta":
-		var event MetaEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return nil, err
+	// 优先使用注册的扩展事件类型
+	event := newRegisteredEvent(&base)
+	if event == nil {
+		switch base.Type {
+		case "message":
+			event = &MessageEvent{}
+		case "notice":
+			event = &NoticeEvent{}
+		case "meta":
+			event = &MetaEvent{}
+		case "request":
+			event = &RequestEvent{}
+		default:
+			// 返回基础事件结构，支持扩展事件类型
+			event = &base
 		}
-		return &event, nil
-	case "request":
-		var event RequestEvent
-		if err := json.Unmarshal(data, &event); err != nil {
+	}
+
+	if event != &base {
+		if err := json.Unmarshal(data, event); err != nil {
 			return nil, err
 		}
-		return &event, nil
-	default:
-		// 返回基础事件结构，支持扩展事件类型
-		return &base, nil
 	}
+	if err := fillExtra(event, data); err != nil {
+		return nil, err
+	}
+	return event, nil
 }
 
 // IsPrivateMessage 判断是否为私聊消息
@@ -103,3 +117,18 @@ func (e *MessageEvent) IsPrivateMessage() bool {
 func (e *MessageEvent) IsGroupMessage() bool {
 	return e.DetailType == "group"
 }
+
+// IsChannelMessage 判断是否为频道消息
+func (e *MessageEvent) IsChannelMessage() bool {
+	return e.DetailType == "channel"
+}
+
+// SessionKey 返回消息所属多轮对话会话的键
+// 私聊按用户区分，群和频道中按群/频道及发送者区分
+func (e *MessageEvent) SessionKey() string {
+	key := e.DetailType + "|" + ConversationKey(e)
+	if e.IsPrivateMessage() {
+		return key
+	}
+	return key + ":" + e.UserID
+}
@@ -172,9 +172,8 @@ func Example_CommandBot() {
 }
 
 func Example_MultiBot() {
-	// 多账号场景
+	// 多账号场景：一个连接承载多个机器人账号
 	client, _ := onebot.New("ws://multi-bot.example.com:5700",
-		onebot.WithSelf("wechat", "bot123456"), // 指定要操作的机器人账号
 		onebot.WithAccessToken("token"),
 	)
 	defer client.Close()
@@ -184,14 +183,31 @@ func Example_MultiBot() {
 		log.Fatal(err)
 	}
 
-	// 监听事件
+	// 为每个账号创建独立的句柄
+	for _, userID := range []string{"bot123456", "bot654321"} {
+		bot := client.Bot(onebot.Self{Platform: "wechat", UserID: userID})
+
+		// 只处理该账号收到的私聊消息，并以该账号回复
+		bot.On("message.private", func(event any) {
+			msg := event.(*onebot.MessageEvent)
+			log.Printf("[%s] 收到消息: %s", bot.Self().UserID, msg.AltMessage)
+
+			reply := onebot.Message{onebot.Text("收到")}
+			if _, err := bot.SendPrivateMessage(msg.UserID, reply); err != nil {
+				log.Printf("发送消息失败: %v", err)
+			}
+		})
+	}
+
+	// 监听所有账号的事件
 	client.On("*", func(event any) {
 		// 处理所有事件
 		switch e := event.(type) {
 		case *onebot.MessageEvent:
 			log.Printf("[%s] 收到消息: %s", e.Self.UserID, e.AltMessage)
-		case *onebot.NoticeEvent:
-			log.Printf("[%s] 收到通知: %s", e.Self.UserID, e.DetailType)
+		case onebot.AnyNotice:
+			notice := e.Notice()
+			log.Printf("[%s] 收到通知: %s", notice.Self.UserID, notice.DetailType)
 		}
 	})
 
@@ -0,0 +1,111 @@
+package onebot
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// eventFields 事件结构体类型到其 JSON 字段名集合的缓存
+var eventFields sync.Map
+
+// Extension 返回扩展字段的值，key 为完整的字段名，如 "wx.avatar"
+func (e *Event) Extension(key string) (any, bool) {
+	value, ok := e.Extra[key]
+	return value, ok
+}
+
+// Extensions 返回指定前缀的扩展字段，返回的键去掉了前缀及其后的 "." 或 "_"
+// 例如 prefix 为 "wx" 时，"wx.avatar" 和 "wx_avatar" 都以 "avatar" 返回
+func (e *Event) Extensions(prefix string) map[string]any {
+	result := make(map[string]any)
+	for key, value := range e.Extra {
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok || name == "" || (name[0] != '.' && name[0] != '_') {
+			continue
+		}
+		result[name[1:]] = value
+	}
+	return result
+}
+
+// Unmarshal 将事件的原始 JSON 解析到 v，用于读取标准结构之外的字段：
+//
+//	var ext struct {
+//		Avatar string `json:"wx.avatar"`
+//	}
+//	msg.Unmarshal(&ext)
+func (e *Event) Unmarshal(v any) error {
+	return json.Unmarshal(e.Raw, v)
+}
+
+// fillExtra 为解析后的事件保存原始 JSON，并收集事件结构体中没有的字段
+func fillExtra(event interface{ Base() *Event }, data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	base := event.Base()
+	base.Raw = slices.Clone(data)
+
+	known := jsonFields(reflect.TypeOf(event).Elem())
+	for key, raw := range fields {
+		if known[key] {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if base.Extra == nil {
+			base.Extra = make(map[string]any)
+		}
+		base.Extra[key] = value
+	}
+	return nil
+}
+
+// jsonFields 返回结构体类型的 JSON 字段名集合，包括嵌入结构体的字段
+func jsonFields(t reflect.Type) map[string]bool {
+	if cached, ok := eventFields.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	collectFields(t, fields)
+	eventFields.Store(t, fields)
+	return fields
+}
+
+// collectFields 收集结构体类型的 JSON 字段名
+func collectFields(t reflect.Type, fields map[string]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectFields(embedded, fields)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+}
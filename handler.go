@@ -0,0 +1,71 @@
+package onebot
+
+// OnMessage 注册消息事件处理器
+func (c *Client) OnMessage(handler func(*MessageEvent)) *Subscription {
+	return c.On("message", typed(handler))
+}
+
+// OnNotice 注册通知事件处理器，各标准通知类型以其嵌入的 NoticeEvent 传入
+// 需要 GuildID、MessageID 等专有字段时使用 Handle 注册具体的通知类型
+func (c *Client) OnNotice(handler func(*NoticeEvent)) *Subscription {
+	return c.On("notice", notice(handler))
+}
+
+// OnRequest 注册请求事件处理器
+func (c *Client) OnRequest(handler func(*RequestEvent)) *Subscription {
+	return c.On("request", typed(handler))
+}
+
+// OnMeta 注册元事件处理器
+func (c *Client) OnMeta(handler func(*MetaEvent)) *Subscription {
+	return c.On("meta", typed(handler))
+}
+
+// Handle 按事件的具体类型注册处理器，T 为 ParseEvent 返回的事件指针类型，如 *MessageEvent
+// T 须实现 Base 方法，传入 MessageEvent 等非指针类型会在编译期报错。处理器只会收到类型为 T 的事件：
+//
+//	onebot.Handle(client, func(msg *onebot.MessageEvent) {
+//		// msg 已是 *MessageEvent，无需类型断言
+//	})
+//
+// 标准通知会解析为 *FriendIncreaseNotice 等具体类型，T 为 *NoticeEvent 时与 OnNotice 相同，
+// 接收所有通知并传入其嵌入的 NoticeEvent
+func Handle[T interface{ Base() *Event }](c *Client, handler func(T)) *Subscription {
+	if h, ok := any(handler).(func(*NoticeEvent)); ok {
+		return c.OnNotice(h)
+	}
+	return c.On("*", typed(handler))
+}
+
+// OnMessage 注册只处理该账号消息事件的处理器
+func (b *Bot) OnMessage(handler func(*MessageEvent)) *Subscription {
+	return b.On("message", typed(handler))
+}
+
+// OnNotice 注册只处理该账号通知事件的处理器
+func (b *Bot) OnNotice(handler func(*NoticeEvent)) *Subscription {
+	return b.On("notice", notice(handler))
+}
+
+// OnRequest 注册只处理该账号请求事件的处理器
+func (b *Bot) OnRequest(handler func(*RequestEvent)) *Subscription {
+	return b.On("request", typed(handler))
+}
+
+// typed 将类型化的处理器转换为 EventHandler，忽略类型不匹配的事件
+func typed[T any](handler func(T)) EventHandler {
+	return func(event any) {
+		if e, ok := event.(T); ok {
+			handler(e)
+		}
+	}
+}
+
+// notice 将通知处理器转换为 EventHandler，接受所有实现 AnyNotice 的事件
+func notice(handler func(*NoticeEvent)) EventHandler {
+	return func(event any) {
+		if e, ok := event.(AnyNotice); ok {
+			handler(e.Notice())
+		}
+	}
+}
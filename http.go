@@ -0,0 +1,67 @@
+package onebot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// isHTTP 判断是否使用 HTTP 通信方式调用动作
+func (c *Client) isHTTP() bool {
+	url := strings.ToLower(c.url)
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// callHTTP 通过 HTTP POST 调用动作
+func (c *Client) callHTTP(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("序列化动作请求失败: %w", err)
+	}
+
+	// 客户端关闭时同时中止请求
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.ctx, cancel)
+	defer stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	c.logger.Debug("发送动作请求", "action", request.Action, "transport", "http")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, contextError(ctx.Err())
+		}
+		return nil, fmt.Errorf("发送 HTTP 动作请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 鉴权失败、Content-Type 不支持等情况实现会返回非 200 状态码
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP 动作请求失败: %s", resp.Status)
+	}
+
+	var response ActionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("解析动作响应失败: %w", err)
+	}
+
+	c.logger.Debug("收到动作响应",
+		"action", request.Action,
+		"status", response.Status,
+		"retcode", response.Retcode)
+
+	return &response, nil
+}
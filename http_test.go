@@ -0,0 +1,371 @@
+package onebot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPAction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		var request ActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("解析动作请求失败: %v", err)
+		}
+		// Connect 时同步机器人状态
+		if request.Action == "get_status" {
+			json.NewEncoder(w).Encode(ActionResponse{Status: "ok", Data: map[string]any{"good": true, "bots": []any{}}})
+			return
+		}
+		if request.Action != "get_version" {
+			t.Errorf("Action 错误: got %s, want %s", request.Action, "get_version")
+		}
+
+		json.NewEncoder(w).Encode(ActionResponse{
+			Status: "ok",
+			Data: map[string]any{
+				"impl":           "test",
+				"version":        "1.0.0",
+				"onebot_version": "12",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithAccessToken("test-token"))
+	defer client.Close()
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("HTTP 模式 Connect 不应失败: %v", err)
+	}
+
+	version, err := client.GetVersion()
+	if err != nil {
+		t.Fatalf("获取版本失败: %v", err)
+	}
+	if version.Impl != "test" {
+		t.Errorf("Impl 错误: got %s, want %s", version.Impl, "test")
+	}
+
+	// 错误的令牌
+	bad, _ := New(server.URL, WithAccessToken("bad-token"))
+	defer bad.Close()
+
+	if _, err := bad.GetVersion(); err == nil {
+		t.Error("鉴权失败时应返回错误")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	client, _ := New("", WithAccessToken("test-token"), WithQuickAction(func(event any) []*ActionRequest {
+		msg, ok := event.(*MessageEvent)
+		if !ok {
+			return nil
+		}
+		return []*ActionRequest{NewActionRequest("send_message", map[string]any{
+			"detail_type": "private",
+			"user_id":     msg.UserID,
+			"message":     Message{Text("pong")},
+		})}
+	}))
+	defer client.Close()
+
+	received := make(chan *MessageEvent, 1)
+	client.On("message.private", func(event any) {
+		received <- event.(*MessageEvent)
+	})
+
+	server := httptest.NewServer(client.WebhookHandler())
+	defer server.Close()
+
+	post := func(token, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("推送 Webhook 失败: %v", err)
+		}
+		return resp
+	}
+
+	event := `{"id":"1","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"ping","alt_message":"ping","user_id":"u1"}`
+
+	resp := post("bad-token", event)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("鉴权失败状态码错误: got %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp = post("test-token", event)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("状态码错误: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var actions []ActionRequest
+	if err := json.NewDecoder(resp.Body).Decode(&actions); err != nil {
+		t.Fatalf("解析快速操作失败: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Action != "send_message" {
+		t.Errorf("快速操作错误: %+v", actions)
+	}
+
+	select {
+	case msg := <-received:
+		if msg.AltMessage != "ping" {
+			t.Errorf("AltMessage 错误: got %s, want %s", msg.AltMessage, "ping")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未收到事件")
+	}
+
+	// 无快速操作时返回 204
+	notice := `{"id":"2","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"notice","detail_type":"friend_increase","sub_type":"","user_id":"u1"}`
+	resp = post("test-token", notice)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("状态码错误: got %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestPolling(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ActionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Action == "get_status" {
+			json.NewEncoder(w).Encode(ActionResponse{Status: "ok", Data: map[string]any{"good": true, "bots": []any{}}})
+			return
+		}
+		if request.Action != "get_latest_events" {
+			t.Errorf("Action 错误: got %s, want %s", request.Action, "get_latest_events")
+		}
+
+		// 第一次失败以触发退避
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(ActionResponse{
+			Status: "ok",
+			Data: []map[string]any{{
+				"id":          "1",
+				"self":        map[string]any{"platform": "qq", "user_id": "bot"},
+				"time":        1,
+				"type":        "message",
+				"detail_type": "group",
+				"sub_type":    "",
+				"message_id":  "m1",
+				"message":     "hello",
+				"alt_message": "hello",
+				"user_id":     "u1",
+				"group_id":    "g1",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithPolling(10, time.Second))
+
+	received := make(chan *MessageEvent, 10)
+	client.On("message.group", func(event any) {
+		received <- event.(*MessageEvent)
+	})
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect 失败: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if msg.GroupID != "g1" {
+			t.Errorf("GroupID 错误: got %s, want %s", msg.GroupID, "g1")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到轮询事件")
+	}
+
+	client.Close()
+}
+
+func TestActionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ActionResponse{
+			Status:  "failed",
+			Retcode: RetcodeUnsupportedAction,
+			Message: "不支持的动作",
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL)
+	defer client.Close()
+
+	err := client.DeleteMessage("m1")
+	if err == nil {
+		t.Fatal("动作失败时应返回错误")
+	}
+
+	if !errors.Is(err, ErrUnsupportedAction) {
+		t.Errorf("errors.Is 应匹配 ErrUnsupportedAction: %v", err)
+	}
+	if errors.Is(err, ErrInternalHandlerError) {
+		t.Errorf("errors.Is 不应匹配 ErrInternalHandlerError: %v", err)
+	}
+	if !IsError(err, RetcodeUnsupportedAction) {
+		t.Errorf("IsError 应返回 true: %v", err)
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("errors.As 应得到 *Error: %v", err)
+	}
+	if e.Action != "delete_message" {
+		t.Errorf("Action 错误: got %s, want %s", e.Action, "delete_message")
+	}
+}
+
+func TestBot(t *testing.T) {
+	selves := make(chan *Self, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ActionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		selves <- request.Self
+		json.NewEncoder(w).Encode(ActionResponse{Status: "ok", Data: map[string]any{"message_id": "m1"}})
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, WithSelf("qq", "default"))
+	defer client.Close()
+
+	bot := client.Bot(Self{Platform: "qq", UserID: "bot2"})
+	if _, err := bot.SendGroupMessage("g1", Message{Text("hi")}); err != nil {
+		t.Fatalf("发送消息失败: %v", err)
+	}
+	if self := <-selves; self == nil || self.UserID != "bot2" {
+		t.Errorf("Bot 动作的 self 错误: %+v", self)
+	}
+
+	// 未通过 Bot 调用时仍使用 WithSelf 的设置
+	if _, err := client.SendGroupMessage("g1", Message{Text("hi")}); err != nil {
+		t.Fatalf("发送消息失败: %v", err)
+	}
+	if self := <-selves; self == nil || self.UserID != "default" {
+		t.Errorf("Client 动作的 self 错误: %+v", self)
+	}
+
+	// 只处理该账号的事件
+	received := make(chan string, 10)
+	bot.On("message.private", func(event any) {
+		received <- event.(*MessageEvent).Self.UserID
+	})
+
+	for _, self := range []string{"bot1", "bot2"} {
+		event, _ := ParseEvent([]byte(`{"id":"` + self + `","self":{"platform":"qq","user_id":"` + self + `"},"time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"hi","alt_message":"hi","user_id":"u1"}`))
+		client.handleEvent(event)
+	}
+
+	select {
+	case self := <-received:
+		if self != "bot2" {
+			t.Errorf("收到其他账号的事件: %s", self)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未收到事件")
+	}
+
+	select {
+	case self := <-received:
+		t.Errorf("收到多余的事件: %s", self)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookDedupQuickAction(t *testing.T) {
+	var calls atomic.Int32
+	client, _ := New("", WithDedup(10, time.Minute), WithQuickAction(func(event any) []*ActionRequest {
+		calls.Add(1)
+		return []*ActionRequest{NewActionRequest("send_message", map[string]any{"message": Message{Text("pong")}})}
+	}))
+	defer client.Close()
+
+	server := httptest.NewServer(client.WebhookHandler())
+	defer server.Close()
+
+	event := `{"id":"1","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"ping","alt_message":"ping","user_id":"u1"}`
+	want := []int{http.StatusOK, http.StatusNoContent}
+	for i, status := range want {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(event))
+		if err != nil {
+			t.Fatalf("推送 Webhook 失败: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("第 %d 次推送状态码错误: got %d, want %d", i+1, resp.StatusCode, status)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("重复事件不应再次执行快速操作: got %d calls", calls.Load())
+	}
+}
+
+func TestWebhookQuickActionInterceptor(t *testing.T) {
+	client, _ := New("", WithQuickAction(func(event any) []*ActionRequest {
+		return []*ActionRequest{
+			NewActionRequest("send_message", map[string]any{"message": Message{Text("pong")}}),
+			NewActionRequest("delete_message", map[string]any{"message_id": "m1"}),
+		}
+	}))
+	defer client.Close()
+
+	var audited []string
+	client.UseAction(func(next ActionInvoker) ActionInvoker {
+		return func(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+			audited = append(audited, request.Action)
+			if request.Action == "delete_message" {
+				return nil, errors.New("禁止撤回")
+			}
+			return next(ctx, request)
+		}
+	})
+
+	server := httptest.NewServer(client.WebhookHandler())
+	defer server.Close()
+
+	event := `{"id":"1","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"ping","alt_message":"ping","user_id":"u1"}`
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(event))
+	if err != nil {
+		t.Fatalf("推送 Webhook 失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var actions []ActionRequest
+	if err := json.NewDecoder(resp.Body).Decode(&actions); err != nil {
+		t.Fatalf("解析快速操作失败: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Action != "send_message" {
+		t.Errorf("拦截器拒绝的快速操作不应返回: %+v", actions)
+	}
+	if !slices.Equal(audited, []string{"send_message", "delete_message"}) {
+		t.Errorf("快速操作应经过动作拦截器: got %v", audited)
+	}
+}
@@ -0,0 +1,30 @@
+package onebot
+
+import "context"
+
+// ActionInvoker 执行动作请求并返回响应的函数
+type ActionInvoker func(ctx context.Context, request *ActionRequest) (*ActionResponse, error)
+
+// ActionInterceptor 动作拦截器
+// 拦截器包装动作的发送流程：可以在调用 next 前修改请求（设置 self、改写参数），
+// 在调用 next 后检查响应（记录日志、统计耗时），多次调用 next 以重试，
+// 或者不调用 next 直接返回响应（如缓存）。
+type ActionInterceptor func(next ActionInvoker) ActionInvoker
+
+// UseAction 注册动作拦截器，作用于通过 Client 和 Bot 发起的所有动作以及 Webhook 快速操作，
+// 先注册的拦截器位于外层。通过 Client 和 Bot 发起的请求已填充 self，ctx 已带有超时时间；
+// 快速操作由 OneBot 实现执行，拦截器收到的是不含执行结果的成功响应
+func (c *Client) UseAction(interceptors ...ActionInterceptor) {
+	c.interceptorMu.Lock()
+	defer c.interceptorMu.Unlock()
+
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// chainActions 以拦截器依次包装动作执行函数
+func chainActions(invoker ActionInvoker, interceptors []ActionInterceptor) ActionInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		invoker = interceptors[i](invoker)
+	}
+	return invoker
+}
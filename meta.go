@@ -0,0 +1,100 @@
+package onebot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// handleMetaEvent 处理客户端自身关心的元事件，在分发给事件处理器之前调用
+func (c *Client) handleMetaEvent(event *MetaEvent) {
+	switch event.DetailType {
+	case "heartbeat":
+		c.feedWatchdog(time.Duration(event.Interval) * time.Millisecond)
+	case "connect":
+		c.handleConnect(event)
+	case "status_update":
+		if event.Status != nil {
+			c.updateBots(event.Status.Bots)
+		}
+	}
+}
+
+// handleConnect 记录 meta.connect 事件中的实现信息，并按需校验 OneBot 版本
+func (c *Client) handleConnect(event *MetaEvent) {
+	if event.Version == nil {
+		c.logger.Warn("meta.connect 事件缺少版本信息")
+		return
+	}
+
+	info := *event.Version
+	c.connInfo.Store(&info)
+	c.logger.Info("OneBot 实现已就绪",
+		"impl", info.Impl,
+		"version", info.Version,
+		"onebot_version", info.OneBotVersion)
+
+	if c.versionCheck && info.OneBotVersion != "12" {
+		c.logger.Error("OneBot 版本不匹配，拒绝连接", "onebot_version", info.OneBotVersion)
+
+		c.mu.RLock()
+		conn := c.conn
+		c.mu.RUnlock()
+		if conn != nil {
+			// 连接断开后不再重连，并以该错误报告断开原因
+			err := fmt.Errorf("%w: %s", ErrVersionMismatch, info.OneBotVersion)
+			c.rejected.Store(&err)
+			// 当前处于读协程中，关闭握手需要异步进行
+			go conn.Close(websocket.StatusPolicyViolation, err.Error())
+		}
+	}
+}
+
+// ConnInfo 返回当前连接的实现信息（来自 meta.connect 事件）
+// 尚未收到 meta.connect 事件或使用 HTTP 通信方式时返回 nil
+func (c *Client) ConnInfo() *GetVersionResponse {
+	return c.connInfo.Load()
+}
+
+// feedWatchdog 收到心跳后重置心跳超时计时器
+func (c *Client) feedWatchdog(interval time.Duration) {
+	if !c.watchdog {
+		return
+	}
+
+	// 仅 WebSocket 连接需要检测，HTTP Webhook 等方式没有长连接
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+	if conn == nil {
+		return
+	}
+
+	if interval <= 0 {
+		interval = c.heartbeat
+	}
+	timeout := 2 * interval
+
+	c.watchdogMu.Lock()
+	defer c.watchdogMu.Unlock()
+
+	if c.watchdogTimer != nil {
+		c.watchdogTimer.Stop()
+	}
+	c.watchdogTimer = time.AfterFunc(timeout, func() {
+		c.logger.Warn("心跳超时，强制断开连接", "timeout", timeout)
+		conn.CloseNow()
+	})
+}
+
+// stopWatchdog 停止心跳超时计时器
+func (c *Client) stopWatchdog() {
+	c.watchdogMu.Lock()
+	defer c.watchdogMu.Unlock()
+
+	if c.watchdogTimer != nil {
+		c.watchdogTimer.Stop()
+		c.watchdogTimer = nil
+	}
+}
@@ -1,6 +1,7 @@
 package onebot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -10,19 +11,24 @@ import (
 
 // SendPrivateMessage 发送私聊消息
 func (c *Client) SendPrivateMessage(userID string, message Message) (*SendMessageResponse, error) {
+	return c.SendPrivateMessageContext(context.Background(), userID, message)
+}
+
+// SendPrivateMessageContext 发送私聊消息（带上下文）
+func (c *Client) SendPrivateMessageContext(ctx context.Context, userID string, message Message) (*SendMessageResponse, error) {
 	params := map[string]any{
 		"detail_type": "private",
 		"user_id":     userID,
 		"message":     message,
 	}
 
-	resp, err := c.Call("send_message", params)
+	resp, err := c.CallContext(ctx, "send_message", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("发送消息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("发送消息失败: %w", newActionError("send_message", resp))
 	}
 
 	var result SendMessageResponse
@@ -35,19 +41,24 @@ func (c *Client) SendPrivateMessage(userID string, message Message) (*SendMessag
 
 // SendGroupMessage 发送群消息
 func (c *Client) SendGroupMessage(groupID string, message Message) (*SendMessageResponse, error) {
+	return c.SendGroupMessageContext(context.Background(), groupID, message)
+}
+
+// SendGroupMessageContext 发送群消息（带上下文）
+func (c *Client) SendGroupMessageContext(ctx context.Context, groupID string, message Message) (*SendMessageResponse, error) {
 	params := map[string]any{
 		"detail_type": "group",
 		"group_id":    groupID,
 		"message":     message,
 	}
 
-	resp, err := c.Call("send_message", params)
+	resp, err := c.CallContext(ctx, "send_message", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("发送消息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("发送消息失败: %w", newActionError("send_message", resp))
 	}
 
 	var result SendMessageResponse
@@ -60,15 +71,20 @@ func (c *Client) SendGroupMessage(groupID string, message Message) (*SendMessage
 
 // SendMessage 通用发送消息
 func (c *Client) SendMessage(detailType string, params map[string]any) (*SendMessageResponse, error) {
+	return c.SendMessageContext(context.Background(), detailType, params)
+}
+
+// SendMessageContext 通用发送消息（带上下文）
+func (c *Client) SendMessageContext(ctx context.Context, detailType string, params map[string]any) (*SendMessageResponse, error) {
 	params["detail_type"] = detailType
 
-	resp, err := c.Call("send_message", params)
+	resp, err := c.CallContext(ctx, "send_message", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("发送消息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("发送消息失败: %w", newActionError("send_message", resp))
 	}
 
 	var result SendMessageResponse
@@ -81,17 +97,22 @@ func (c *Client) SendMessage(detailType string, params map[string]any) (*SendMes
 
 // DeleteMessage 撤回消息
 func (c *Client) DeleteMessage(messageID string) error {
+	return c.DeleteMessageContext(context.Background(), messageID)
+}
+
+// DeleteMessageContext 撤回消息（带上下文）
+func (c *Client) DeleteMessageContext(ctx context.Context, messageID string) error {
 	params := map[string]any{
 		"message_id": messageID,
 	}
 
-	resp, err := c.Call("delete_message", params)
+	resp, err := c.CallContext(ctx, "delete_message", params)
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsOK() {
-		return fmt.Errorf("撤回消息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return fmt.Errorf("撤回消息失败: %w", newActionError("delete_message", resp))
 	}
 
 	return nil
@@ -101,13 +122,18 @@ func (c *Client) DeleteMessage(messageID string) error {
 
 // GetVersion 获取版本信息
 func (c *Client) GetVersion() (*GetVersionResponse, error) {
-	resp, err := c.Call("get_version", nil)
+	return c.GetVersionContext(context.Background())
+}
+
+// GetVersionContext 获取版本信息（带上下文）
+func (c *Client) GetVersionContext(ctx context.Context) (*GetVersionResponse, error) {
+	resp, err := c.CallContext(ctx, "get_version", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取版本失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取版本失败: %w", newActionError("get_version", resp))
 	}
 
 	var result GetVersionResponse
@@ -120,13 +146,18 @@ func (c *Client) GetVersion() (*GetVersionResponse, error) {
 
 // GetStatus 获取状态
 func (c *Client) GetStatus() (*GetStatusResponse, error) {
-	resp, err := c.Call("get_status", nil)
+	return c.GetStatusContext(context.Background())
+}
+
+// GetStatusContext 获取状态（带上下文）
+func (c *Client) GetStatusContext(ctx context.Context) (*GetStatusResponse, error) {
+	resp, err := c.CallContext(ctx, "get_status", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取状态失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取状态失败: %w", newActionError("get_status", resp))
 	}
 
 	var result GetStatusResponse
@@ -139,13 +170,18 @@ func (c *Client) GetStatus() (*GetStatusResponse, error) {
 
 // GetSupportedActions 获取支持的动作列表
 func (c *Client) GetSupportedActions() ([]string, error) {
-	resp, err := c.Call("get_supported_actions", nil)
+	return c.GetSupportedActionsContext(context.Background())
+}
+
+// GetSupportedActionsContext 获取支持的动作列表（带上下文）
+func (c *Client) GetSupportedActionsContext(ctx context.Context) ([]string, error) {
+	resp, err := c.CallContext(ctx, "get_supported_actions", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取支持的动作失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取支持的动作失败: %w", newActionError("get_supported_actions", resp))
 	}
 
 	var result []string
@@ -158,18 +194,30 @@ func (c *Client) GetSupportedActions() ([]string, error) {
 
 // GetLatestEvents 获取最新事件（仅 HTTP 通信方式支持）
 func (c *Client) GetLatestEvents(limit int, timeout time.Duration) ([]any, error) {
+	return c.GetLatestEventsContext(context.Background(), limit, timeout)
+}
+
+// GetLatestEventsContext 获取最新事件（带上下文，仅 HTTP 通信方式支持）
+// ctx 未设置截止时间时，等待时间为 timeout 额外加 5 秒
+func (c *Client) GetLatestEventsContext(ctx context.Context, limit int, timeout time.Duration) ([]any, error) {
 	params := map[string]any{
 		"limit":   limit,
 		"timeout": int64(timeout.Seconds()),
 	}
 
-	resp, err := c.CallWithTimeout("get_latest_events", params, timeout+5*time.Second)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+5*time.Second)
+		defer cancel()
+	}
+
+	resp, err := c.CallContext(ctx, "get_latest_events", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取最新事件失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取最新事件失败: %w", newActionError("get_latest_events", resp))
 	}
 
 	// 解析事件列表
@@ -185,20 +233,25 @@ func (c *Client) GetLatestEvents(limit int, timeout time.Duration) ([]any, error
 		return events, nil
 	}
 
-	return nil, fmt.Errorf("响应数据格式错误")
+	return nil, fmt.Errorf("获取最新事件失败: %w", ErrInvalidResponse)
 }
 
 // 用户信息方法
 
 // GetSelfInfo 获取机器人自身信息
 func (c *Client) GetSelfInfo() (*GetSelfInfoResponse, error) {
-	resp, err := c.Call("get_self_info", nil)
+	return c.GetSelfInfoContext(context.Background())
+}
+
+// GetSelfInfoContext 获取机器人自身信息（带上下文）
+func (c *Client) GetSelfInfoContext(ctx context.Context) (*GetSelfInfoResponse, error) {
+	resp, err := c.CallContext(ctx, "get_self_info", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取自身信息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取自身信息失败: %w", newActionError("get_self_info", resp))
 	}
 
 	var result GetSelfInfoResponse
@@ -211,17 +264,22 @@ func (c *Client) GetSelfInfo() (*GetSelfInfoResponse, error) {
 
 // GetUserInfo 获取用户信息
 func (c *Client) GetUserInfo(userID string) (*GetUserInfoResponse, error) {
+	return c.GetUserInfoContext(context.Background(), userID)
+}
+
+// GetUserInfoContext 获取用户信息（带上下文）
+func (c *Client) GetUserInfoContext(ctx context.Context, userID string) (*GetUserInfoResponse, error) {
 	params := map[string]any{
 		"user_id": userID,
 	}
 
-	resp, err := c.Call("get_user_info", params)
+	resp, err := c.CallContext(ctx, "get_user_info", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取用户信息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取用户信息失败: %w", newActionError("get_user_info", resp))
 	}
 
 	var result GetUserInfoResponse
@@ -234,13 +292,18 @@ func (c *Client) GetUserInfo(userID string) (*GetUserInfoResponse, error) {
 
 // GetFriendList 获取好友列表
 func (c *Client) GetFriendList() ([]GetUserInfoResponse, error) {
-	resp, err := c.Call("get_friend_list", nil)
+	return c.GetFriendListContext(context.Background())
+}
+
+// GetFriendListContext 获取好友列表（带上下文）
+func (c *Client) GetFriendListContext(ctx context.Context) ([]GetUserInfoResponse, error) {
+	resp, err := c.CallContext(ctx, "get_friend_list", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取好友列表失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取好友列表失败: %w", newActionError("get_friend_list", resp))
 	}
 
 	var result []GetUserInfoResponse
@@ -255,17 +318,22 @@ func (c *Client) GetFriendList() ([]GetUserInfoResponse, error) {
 
 // GetGroupInfo 获取群信息
 func (c *Client) GetGroupInfo(groupID string) (*GetGroupInfoResponse, error) {
+	return c.GetGroupInfoContext(context.Background(), groupID)
+}
+
+// GetGroupInfoContext 获取群信息（带上下文）
+func (c *Client) GetGroupInfoContext(ctx context.Context, groupID string) (*GetGroupInfoResponse, error) {
 	params := map[string]any{
 		"group_id": groupID,
 	}
 
-	resp, err := c.Call("get_group_info", params)
+	resp, err := c.CallContext(ctx, "get_group_info", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取群信息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取群信息失败: %w", newActionError("get_group_info", resp))
 	}
 
 	var result GetGroupInfoResponse
@@ -278,13 +346,18 @@ func (c *Client) GetGroupInfo(groupID string) (*GetGroupInfoResponse, error) {
 
 // GetGroupList 获取群列表
 func (c *Client) GetGroupList() ([]GetGroupInfoResponse, error) {
-	resp, err := c.Call("get_group_list", nil)
+	return c.GetGroupListContext(context.Background())
+}
+
+// GetGroupListContext 获取群列表（带上下文）
+func (c *Client) GetGroupListContext(ctx context.Context) ([]GetGroupInfoResponse, error) {
+	resp, err := c.CallContext(ctx, "get_group_list", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取群列表失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取群列表失败: %w", newActionError("get_group_list", resp))
 	}
 
 	var result []GetGroupInfoResponse
@@ -297,18 +370,23 @@ func (c *Client) GetGroupList() ([]GetGroupInfoResponse, error) {
 
 // GetGroupMemberInfo 获取群成员信息
 func (c *Client) GetGroupMemberInfo(groupID, userID string) (*GetGroupMemberInfoResponse, error) {
+	return c.GetGroupMemberInfoContext(context.Background(), groupID, userID)
+}
+
+// GetGroupMemberInfoContext 获取群成员信息（带上下文）
+func (c *Client) GetGroupMemberInfoContext(ctx context.Context, groupID, userID string) (*GetGroupMemberInfoResponse, error) {
 	params := map[string]any{
 		"group_id": groupID,
 		"user_id":  userID,
 	}
 
-	resp, err := c.Call("get_group_member_info", params)
+	resp, err := c.CallContext(ctx, "get_group_member_info", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取群成员信息失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取群成员信息失败: %w", newActionError("get_group_member_info", resp))
 	}
 
 	var result GetGroupMemberInfoResponse
@@ -321,17 +399,22 @@ func (c *Client) GetGroupMemberInfo(groupID, userID string) (*GetGroupMemberInfo
 
 // GetGroupMemberList 获取群成员列表
 func (c *Client) GetGroupMemberList(groupID string) ([]GetGroupMemberInfoResponse, error) {
+	return c.GetGroupMemberListContext(context.Background(), groupID)
+}
+
+// GetGroupMemberListContext 获取群成员列表（带上下文）
+func (c *Client) GetGroupMemberListContext(ctx context.Context, groupID string) ([]GetGroupMemberInfoResponse, error) {
 	params := map[string]any{
 		"group_id": groupID,
 	}
 
-	resp, err := c.Call("get_group_member_list", params)
+	resp, err := c.CallContext(ctx, "get_group_member_list", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取群成员列表失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取群成员列表失败: %w", newActionError("get_group_member_list", resp))
 	}
 
 	var result []GetGroupMemberInfoResponse
@@ -346,19 +429,24 @@ func (c *Client) GetGroupMemberList(groupID string) ([]GetGroupMemberInfoRespons
 
 // UploadFile 上传文件
 func (c *Client) UploadFile(fileType string, name string, url string) (*UploadFileResponse, error) {
+	return c.UploadFileContext(context.Background(), fileType, name, url)
+}
+
+// UploadFileContext 上传文件（带上下文）
+func (c *Client) UploadFileContext(ctx context.Context, fileType string, name string, url string) (*UploadFileResponse, error) {
 	params := map[string]any{
 		"type": fileType,
 		"name": name,
 		"url":  url,
 	}
 
-	resp, err := c.Call("upload_file", params)
+	resp, err := c.CallContext(ctx, "upload_file", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("上传文件失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("上传文件失败: %w", newActionError("upload_file", resp))
 	}
 
 	var result UploadFileResponse
@@ -371,18 +459,23 @@ func (c *Client) UploadFile(fileType string, name string, url string) (*UploadFi
 
 // GetFile 获取文件
 func (c *Client) GetFile(fileID string, fileType string) (*GetFileResponse, error) {
+	return c.GetFileContext(context.Background(), fileID, fileType)
+}
+
+// GetFileContext 获取文件（带上下文）
+func (c *Client) GetFileContext(ctx context.Context, fileID string, fileType string) (*GetFileResponse, error) {
 	params := map[string]any{
 		"file_id": fileID,
 		"type":    fileType,
 	}
 
-	resp, err := c.Call("get_file", params)
+	resp, err := c.CallContext(ctx, "get_file", params)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsOK() {
-		return nil, fmt.Errorf("获取文件失败: %s (code: %d)", resp.Message, resp.Retcode)
+		return nil, fmt.Errorf("获取文件失败: %w", newActionError("get_file", resp))
 	}
 
 	var result GetFileResponse
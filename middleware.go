@@ -0,0 +1,32 @@
+package onebot
+
+// Middleware 事件中间件
+// 中间件包装后续的处理流程：可以在调用 next 前后执行公共逻辑（日志、鉴权、统计等），
+// 以修改或替换后的事件调用 next，或者不调用 next 以中断处理。
+// 返回的错误和 panic 会交给 WithErrorHandler 设置的错误处理函数。
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use 注册全局事件中间件，在匹配处理器之前执行，先注册的中间件位于外层
+func (c *Client) Use(middleware ...Middleware) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// UseOn 为指定事件键注册中间件，只包装通过 On 注册在该键下的处理器
+// eventType 的含义与 On 相同，先注册的中间件位于外层
+func (c *Client) UseOn(eventType string, middleware ...Middleware) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+
+	c.keyMiddleware[eventType] = append(c.keyMiddleware[eventType], middleware...)
+}
+
+// chain 以中间件依次包装处理函数
+func chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,118 @@
+package onebot
+
+// AnyNotice 所有通知事件都实现的接口，包括 NoticeEvent 及各标准通知类型
+type AnyNotice interface {
+	Notice() *NoticeEvent
+}
+
+// Notice 返回通知事件的公共结构
+// 各标准通知类型都嵌入了 NoticeEvent，因此都会获得该方法
+func (e *NoticeEvent) Notice() *NoticeEvent {
+	return e
+}
+
+// FriendIncreaseNotice 好友增加（notice.friend_increase）
+type FriendIncreaseNotice struct {
+	NoticeEvent
+}
+
+// FriendDecreaseNotice 好友减少（notice.friend_decrease）
+type FriendDecreaseNotice struct {
+	NoticeEvent
+}
+
+// PrivateMessageDeleteNotice 私聊消息删除（notice.private_message_delete）
+type PrivateMessageDeleteNotice struct {
+	NoticeEvent
+	MessageID string `json:"message_id"` // 消息 ID
+}
+
+// GroupMemberIncreaseNotice 群成员增加（notice.group_member_increase），子类型为 join/invite
+type GroupMemberIncreaseNotice struct {
+	NoticeEvent
+}
+
+// GroupMemberDecreaseNotice 群成员减少（notice.group_member_decrease），子类型为 leave/kick
+type GroupMemberDecreaseNotice struct {
+	NoticeEvent
+}
+
+// GroupMessageDeleteNotice 群消息删除（notice.group_message_delete），子类型为 recall/delete
+type GroupMessageDeleteNotice struct {
+	NoticeEvent
+	MessageID string `json:"message_id"` // 消息 ID
+}
+
+// GuildMemberIncreaseNotice 群组成员增加（notice.guild_member_increase），子类型为 join/invite
+type GuildMemberIncreaseNotice struct {
+	NoticeEvent
+	GuildID string `json:"guild_id"` // 群组 ID
+}
+
+// GuildMemberDecreaseNotice 群组成员减少（notice.guild_member_decrease），子类型为 leave/kick
+type GuildMemberDecreaseNotice struct {
+	NoticeEvent
+	GuildID string `json:"guild_id"` // 群组 ID
+}
+
+// ChannelMemberIncreaseNotice 频道成员增加（notice.channel_member_increase），子类型为 join/invite
+type ChannelMemberIncreaseNotice struct {
+	NoticeEvent
+	GuildID   string `json:"guild_id"`   // 群组 ID
+	ChannelID string `json:"channel_id"` // 频道 ID
+}
+
+// ChannelMemberDecreaseNotice 频道成员减少（notice.channel_member_decrease），子类型为 leave/kick
+type ChannelMemberDecreaseNotice struct {
+	NoticeEvent
+	GuildID   string `json:"guild_id"`   // 群组 ID
+	ChannelID string `json:"channel_id"` // 频道 ID
+}
+
+// ChannelMessageDeleteNotice 频道消息删除（notice.channel_message_delete），子类型为 recall/delete
+type ChannelMessageDeleteNotice struct {
+	NoticeEvent
+	GuildID   string `json:"guild_id"`   // 群组 ID
+	ChannelID string `json:"channel_id"` // 频道 ID
+	MessageID string `json:"message_id"` // 消息 ID
+}
+
+// ChannelCreateNotice 频道新建（notice.channel_create）
+type ChannelCreateNotice struct {
+	NoticeEvent
+	GuildID   string `json:"guild_id"`   // 群组 ID
+	ChannelID string `json:"channel_id"` // 频道 ID
+}
+
+// ChannelDeleteNotice 频道删除（notice.channel_delete）
+type ChannelDeleteNotice struct {
+	NoticeEvent
+	GuildID   string `json:"guild_id"`   // 群组 ID
+	ChannelID string `json:"channel_id"` // 频道 ID
+}
+
+// scope 返回通知所在的群组和频道，用于计算会话键
+func (e *GuildMemberIncreaseNotice) scope() (string, string)   { return e.GuildID, "" }
+func (e *GuildMemberDecreaseNotice) scope() (string, string)   { return e.GuildID, "" }
+func (e *ChannelMemberIncreaseNotice) scope() (string, string) { return e.GuildID, e.ChannelID }
+func (e *ChannelMemberDecreaseNotice) scope() (string, string) { return e.GuildID, e.ChannelID }
+func (e *ChannelMessageDeleteNotice) scope() (string, string)  { return e.GuildID, e.ChannelID }
+func (e *ChannelCreateNotice) scope() (string, string)         { return e.GuildID, e.ChannelID }
+func (e *ChannelDeleteNotice) scope() (string, string)         { return e.GuildID, e.ChannelID }
+
+// 注册标准通知类型，ParseEvent 据此返回对应的结构体
+func init() {
+	RegisterEvent[FriendIncreaseNotice]("notice.friend_increase")
+	RegisterEvent[FriendDecreaseNotice]("notice.friend_decrease")
+	RegisterEvent[PrivateMessageDeleteNotice]("notice.private_message_delete")
+	RegisterEvent[GroupMemberIncreaseNotice]("notice.group_member_increase")
+	RegisterEvent[GroupMemberDecreaseNotice]("notice.group_member_decrease")
+	RegisterEvent[GroupMessageDeleteNotice]("notice.group_message_delete")
+	RegisterEvent[GuildMemberIncreaseNotice]("notice.guild_member_increase")
+	RegisterEvent[GuildMemberDecreaseNotice]("notice.guild_member_decrease")
+	RegisterEvent[ChannelMemberIncreaseNotice]("notice.channel_member_increase")
+	RegisterEvent[ChannelMemberDecreaseNotice]("notice.channel_member_decrease")
+	RegisterEvent[ChannelMessageDeleteNotice]("notice.channel_message_delete")
+	RegisterEvent[ChannelCreateNotice]("notice.channel_create")
+	RegisterEvent[ChannelDeleteNotice]("notice.channel_delete")
+}
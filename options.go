@@ -2,6 +2,7 @@ package onebot
 
 import (
 	"log/slog"
+	"net/http"
 	"time"
 )
 
@@ -39,23 +40,192 @@ func WithReconnect(enabled bool) Option {
 	}
 }
 
-// WithReconnectWait 设置重连等待时间
+// WithReconnectWait 设置重连等待时间（固定间隔，设置了 WithBackoff 时不生效）
 func WithReconnectWait(wait time.Duration) Option {
 	return func(c *Client) {
 		c.reconnectWait = wait
 	}
 }
 
-// WithHeartbeat 设置心跳间隔
+// WithBackoff 设置重连退避策略
+func WithBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.backoff = backoff
+	}
+}
+
+// WithExponentialBackoff 使用指数退避重连，等待时间从 initial 开始翻倍，最长不超过 max
+func WithExponentialBackoff(initial, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoff = ExponentialBackoff{
+			Initial: initial,
+			Max:     max,
+		}
+	}
+}
+
+// WithReconnectJitter 设置重连等待时间的随机抖动比例（0~1）
+// 例如 0.2 表示在退避时间的 ±20% 范围内随机，避免多个客户端同时重连
+func WithReconnectJitter(ratio float64) Option {
+	return func(c *Client) {
+		c.jitter = ratio
+	}
+}
+
+// WithMaxReconnectAttempts 设置最大重连次数，0 表示不限制
+func WithMaxReconnectAttempts(attempts int) Option {
+	return func(c *Client) {
+		c.maxReconnectAttempts = attempts
+	}
+}
+
+// WithGiveUp 设置放弃重连时的回调，参数为已尝试的次数和最后一次重连的错误
+// 因 OneBot 版本不匹配放弃时，attempts 为 0，err 匹配 ErrVersionMismatch
+func WithGiveUp(fn func(attempts int, err error)) Option {
+	return func(c *Client) {
+		c.onGiveUp = fn
+	}
+}
+
+// WithResendActions 设置连接断开后需要在重连后重发的动作
+// 仅应指定幂等的动作（如 get_status、get_group_info），未指定的动作在断开时立即返回 ErrConnectionLost
+// 未启用自动重连时不会保留动作；放弃重连时等待重发的动作也立即返回 ErrConnectionLost
+func WithResendActions(actions ...string) Option {
+	return func(c *Client) {
+		if c.resendActions == nil {
+			c.resendActions = make(map[string]bool)
+		}
+		for _, action := range actions {
+			c.resendActions[action] = true
+		}
+	}
+}
+
+// WithHeartbeat 设置默认心跳间隔
+// meta.heartbeat 事件未携带 interval 时，用于计算心跳超时时间
 func WithHeartbeat(interval time.Duration) Option {
 	return func(c *Client) {
 		c.heartbeat = interval
 	}
 }
 
+// WithHeartbeatWatchdog 设置是否启用心跳超时检测（默认启用）
+// 启用后，收到第一个 meta.heartbeat 事件起，超过两个心跳周期未收到心跳即视为连接失效，
+// 强制断开并按重连策略重连
+func WithHeartbeatWatchdog(enabled bool) Option {
+	return func(c *Client) {
+		c.watchdog = enabled
+	}
+}
+
+// WithVersionCheck 设置是否校验 OneBot 版本
+// 启用后，meta.connect 事件中 onebot_version 不为 "12" 时主动断开连接并放弃重连，
+// 状态变为 StateGaveUp，错误匹配 ErrVersionMismatch；反向 WebSocket 模式下由 OneBot 实现重连，
+// 每次重连都会因版本不匹配再次被断开
+func WithVersionCheck(enabled bool) Option {
+	return func(c *Client) {
+		c.versionCheck = enabled
+	}
+}
+
+// WithWorkers 使用固定大小的工作池分发事件
+// 默认每个事件在独立协程中处理；启用工作池后，同一会话（见 WithOrderKey）
+// 的事件由同一个工作协程按接收顺序处理。两种方式下同一事件的多个处理器都按注册顺序依次执行
+func WithWorkers(workers int) Option {
+	return func(c *Client) {
+		c.workers = workers
+	}
+}
+
+// WithQueueSize 设置工作池中每个工作协程的事件队列长度（默认 100）
+func WithQueueSize(size int) Option {
+	return func(c *Client) {
+		c.queueSize = size
+	}
+}
+
+// WithOverflowPolicy 设置工作池队列已满时的处理策略（默认 OverflowDropNewest）
+// OverflowBlock 会阻塞接收事件的协程，WebSocket 连接上的动作响应也随之暂停，
+// 此时在处理器中调用动作可能一直等到超时，仅适合处理器不调用动作的场景
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(c *Client) {
+		c.overflow = policy
+	}
+}
+
+// WithOrderKey 设置工作池保证顺序的会话键函数（默认 ConversationKey）
+// 键相同的事件按接收顺序依次处理，返回空字符串表示不需要保证顺序
+func WithOrderKey(fn func(event any) string) Option {
+	return func(c *Client) {
+		c.orderKeyFunc = fn
+	}
+}
+
+// WithDedup 启用基于内存的事件去重，记录最近 size 个事件 ID，ttl 内 ID 相同的事件只分发一次
+func WithDedup(size int, ttl time.Duration) Option {
+	return WithDedupStore(NewMemoryDedupStore(size), ttl)
+}
+
+// WithDedupStore 使用自定义存储启用事件去重，多个进程共享同一存储即可跨进程去重
+func WithDedupStore(store DedupStore, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.dedup = store
+		c.dedupTTL = ttl
+	}
+}
+
+// WithErrorHandler 设置事件处理器的错误处理函数
+// 处理器返回错误或发生 panic（错误类型为 *PanicError）时调用，event 为正在处理的事件
+func WithErrorHandler(fn func(event any, err error)) Option {
+	return func(c *Client) {
+		c.errorHandler = fn
+	}
+}
+
+// WithSessionTimeout 设置会话的超时时间，默认为 5 分钟，0 表示不限制
+// 既是 Next 等待下一条消息的默认超时时间，也是会话空闲多久后自动结束
+func WithSessionTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.sessionTimeout = timeout
+	}
+}
+
+// WithCancelKeywords 设置会话的取消关键词，会话收到内容为关键词的消息时结束
+func WithCancelKeywords(keywords ...string) Option {
+	return func(c *Client) {
+		c.cancelKeywords = keywords
+	}
+}
+
 // WithTimeout 设置默认超时时间
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
 		c.timeout = timeout
 	}
 }
+
+// WithHTTPClient 设置 HTTP 通信方式使用的 HTTP 客户端
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithQuickAction 设置 HTTP Webhook 的快速操作函数
+// 函数返回的动作请求依次经过 UseAction 注册的动作拦截器，再作为 Webhook 响应体交由 OneBot 实现执行
+func WithQuickAction(fn QuickActionFunc) Option {
+	return func(c *Client) {
+		c.quickAction = fn
+	}
+}
+
+// WithPolling 启用 HTTP 轮询事件（仅 HTTP 通信方式）
+// Connect 后循环调用 get_latest_events，limit 为每次获取的最大事件数（0 表示不限制），
+// timeout 为没有事件时实现端等待的时间
+func WithPolling(limit int, timeout time.Duration) Option {
+	return func(c *Client) {
+		c.polling = true
+		c.pollLimit = limit
+		c.pollTimeout = timeout
+	}
+}
@@ -0,0 +1,56 @@
+package onebot
+
+import "time"
+
+// 轮询退避参数
+const (
+	pollIdleWait   = time.Second      // 不等待模式下没有事件时的轮询间隔
+	pollMinBackoff = time.Second      // 轮询失败后的初始等待时间
+	pollMaxBackoff = 30 * time.Second // 轮询失败后的最大等待时间
+)
+
+// pollLoop 通过 get_latest_events 轮询事件，直到客户端关闭
+func (c *Client) pollLoop() {
+	c.logger.Info("开始轮询事件", "limit", c.pollLimit, "timeout", c.pollTimeout)
+
+	backoff := pollMinBackoff
+	for !c.closed.Load() {
+		events, err := c.GetLatestEventsContext(c.ctx, c.pollLimit, c.pollTimeout)
+		if err != nil {
+			if c.closed.Load() {
+				return
+			}
+			c.logger.Error("轮询事件失败", "error", err, "wait", backoff)
+			if !c.sleep(backoff) {
+				return
+			}
+			backoff = min(backoff*2, pollMaxBackoff)
+			continue
+		}
+		backoff = pollMinBackoff
+
+		for _, event := range events {
+			c.handleEvent(event)
+		}
+
+		// 实现端不等待时避免空转
+		if len(events) == 0 && c.pollTimeout <= 0 {
+			if !c.sleep(pollIdleWait) {
+				return
+			}
+		}
+	}
+}
+
+// sleep 等待指定时间，客户端关闭时提前返回 false
+func (c *Client) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-c.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,51 @@
+package onebot
+
+import "sync"
+
+// eventTypes 已注册的事件类型，键为 "type.detail_type" 或 "type.detail_type.sub_type"
+var (
+	eventTypes   = make(map[string]func() interface{ Base() *Event })
+	eventTypesMu sync.RWMutex
+)
+
+// RegisterEvent 为扩展事件注册 Go 类型，key 形如 "notice.wx.pat"（type.detail_type）
+// 或 "notice.group_member_increase.invite"（type.detail_type.sub_type）
+// T 须嵌入 Event 或 MessageEvent 等标准事件，ParseEvent 会将匹配的事件解析为 *T，
+// 带子类型的注册优先。通常在 init 中调用：
+//
+//	type PatNotice struct {
+//		onebot.NoticeEvent
+//		TargetID string `json:"wx.target_id"`
+//	}
+//
+//	onebot.RegisterEvent[PatNotice]("notice.wx.pat")
+//	onebot.Handle(client, func(notice *PatNotice) { ... })
+func RegisterEvent[T any, PT interface {
+	*T
+	Base() *Event
+}](key string) {
+	eventTypesMu.Lock()
+	defer eventTypesMu.Unlock()
+
+	eventTypes[key] = func() interface{ Base() *Event } {
+		return PT(new(T))
+	}
+}
+
+// newRegisteredEvent 按事件类型查找已注册的事件类型并创建实例，未注册时返回 nil
+func newRegisteredEvent(base *Event) interface{ Base() *Event } {
+	key := base.Type + "." + base.DetailType
+
+	eventTypesMu.RLock()
+	defer eventTypesMu.RUnlock()
+
+	if base.SubType != "" {
+		if factory, ok := eventTypes[key+"."+base.SubType]; ok {
+			return factory()
+		}
+	}
+	if factory, ok := eventTypes[key]; ok {
+		return factory()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package onebot
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coder/websocket"
+)
+
+// ReverseHandler 返回反向 WebSocket 服务端的 HTTP 处理器
+// OneBot 实现作为 WebSocket 客户端主动连接到该处理器，连接建立后事件分发与动作调用
+// 与正向 WebSocket 完全一致。同一时间只接受一个连接，已有连接时新的连接请求返回 409，
+// 避免多个实现互相替换；旧连接失效后（如心跳超时）即可重新连接。
+func (c *Client) ReverseHandler() http.Handler {
+	c.reverse.Store(true)
+	return http.HandlerFunc(c.serveReverse)
+}
+
+// ListenReverse 在指定地址启动反向 WebSocket 服务器，阻塞直到服务器关闭
+// 调用 Close 后返回 http.ErrServerClosed
+func (c *Client) ListenReverse(addr string) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: c.ReverseHandler(),
+	}
+
+	c.mu.Lock()
+	if c.closed.Load() {
+		c.mu.Unlock()
+		return http.ErrServerClosed
+	}
+	c.server = server
+	c.mu.Unlock()
+
+	c.logger.Info("反向 WebSocket 服务器已启动", "addr", addr)
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("反向 WebSocket 服务器异常退出: %w", err)
+	}
+
+	return http.ErrServerClosed
+}
+
+// serveReverse 处理 OneBot 实现发起的反向 WebSocket 连接
+func (c *Client) serveReverse(w http.ResponseWriter, r *http.Request) {
+	if c.closed.Load() {
+		http.Error(w, "客户端已关闭", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !c.authorized(r) {
+		c.logger.Warn("反向 WebSocket 鉴权失败", "remote", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 握手前占用连接槽位，避免同时到达的两个握手都通过检查后互相替换
+	if !c.accepting.CompareAndSwap(false, true) {
+		c.logger.Warn("已有 OneBot 实现通过反向 WebSocket 连接，拒绝新连接", "remote", r.RemoteAddr)
+		http.Error(w, "已有 OneBot 实现连接", http.StatusConflict)
+		return
+	}
+	defer c.accepting.Store(false)
+
+	opts := &websocket.AcceptOptions{}
+	// OneBot 12 实现通过子协议 12.<impl> 声明版本，服务端需原样回应
+	if protocol := onebotSubprotocol(r); protocol != "" {
+		opts.Subprotocols = []string{protocol}
+	}
+
+	conn, err := websocket.Accept(w, r, opts)
+	if err != nil {
+		c.logger.Error("接受反向 WebSocket 连接失败", "error", err, "remote", r.RemoteAddr)
+		return
+	}
+
+	c.attach(conn)
+	c.logger.Info("OneBot 实现已通过反向 WebSocket 连接",
+		"remote", r.RemoteAddr,
+		"impl", r.Header.Get("X-Impl"),
+		"protocol", conn.Subprotocol())
+
+	// 阻塞到连接断开，使连接生命周期与请求一致
+	c.readLoop(conn)
+}
+
+// authorized 校验请求携带的访问令牌
+// 支持 Authorization: Bearer <token> 请求头和 access_token 查询参数两种方式
+func (c *Client) authorized(r *http.Request) bool {
+	if c.accessToken == "" {
+		return true
+	}
+
+	var token string
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	} else {
+		token = r.URL.Query().Get("access_token")
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(c.accessToken)) == 1
+}
+
+// onebotSubprotocol 返回请求中声明的 OneBot 12 子协议
+func onebotSubprotocol(r *http.Request) string {
+	for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(value, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if strings.HasPrefix(protocol, "12.") {
+				return protocol
+			}
+		}
+	}
+	return ""
+}
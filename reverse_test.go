@@ -0,0 +1,489 @@
+package onebot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestReverseWebSocket(t *testing.T) {
+	client, _ := New("", WithAccessToken("test-token"), WithTimeout(5*time.Second))
+	defer client.Close()
+
+	server := httptest.NewServer(client.ReverseHandler())
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 未携带令牌应被拒绝
+	_, resp, err := websocket.Dial(ctx, wsURL, nil)
+	if err == nil {
+		t.Fatal("未鉴权的连接应该失败")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("未鉴权连接的状态码错误: %v", resp)
+	}
+
+	// 模拟 OneBot 实现连接
+	impl, _, err := websocket.Dial(ctx, wsURL+"?access_token=test-token", &websocket.DialOptions{
+		Subprotocols: []string{"12.test"},
+	})
+	if err != nil {
+		t.Fatalf("反向 WebSocket 连接失败: %v", err)
+	}
+	defer impl.CloseNow()
+
+	if impl.Subprotocol() != "12.test" {
+		t.Errorf("子协议错误: got %s, want %s", impl.Subprotocol(), "12.test")
+	}
+
+	received := make(chan *MessageEvent, 1)
+	client.On("message.private", func(event any) {
+		received <- event.(*MessageEvent)
+	})
+
+	// 推送事件
+	event := `{"id":"1","self":{"platform":"qq","user_id":"bot"},"time":1,"type":"message","detail_type":"private","sub_type":"","message_id":"m1","message":"hi","alt_message":"hi","user_id":"u1"}`
+	if err := impl.Write(ctx, websocket.MessageText, []byte(event)); err != nil {
+		t.Fatalf("推送事件失败: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if msg.UserID != "u1" {
+			t.Errorf("UserID 错误: got %s, want %s", msg.UserID, "u1")
+		}
+	case <-ctx.Done():
+		t.Fatal("未收到事件")
+	}
+
+	// 模拟实现响应动作
+	go func() {
+		for {
+			_, data, err := impl.Read(ctx)
+			if err != nil {
+				return
+			}
+			var request ActionRequest
+			json.Unmarshal(data, &request)
+			response, _ := json.Marshal(ActionResponse{
+				Status: "ok",
+				Data:   map[string]any{"message_id": "m2", "time": 2},
+				Echo:   request.Echo,
+			})
+			impl.Write(ctx, websocket.MessageText, response)
+		}
+	}()
+
+	result, err := client.SendPrivateMessage("u1", Message{Text("hello")})
+	if err != nil {
+		t.Fatalf("发送消息失败: %v", err)
+	}
+	if result.MessageID != "m2" {
+		t.Errorf("MessageID 错误: got %s, want %s", result.MessageID, "m2")
+	}
+}
+
+func TestConnStateChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		// 建立连接后立即断开
+		conn.Close(websocket.StatusGoingAway, "bye")
+	}))
+
+	client, _ := New("ws"+strings.TrimPrefix(server.URL, "http"),
+		WithExponentialBackoff(time.Millisecond, 10*time.Millisecond),
+		WithMaxReconnectAttempts(2),
+	)
+	defer client.Close()
+
+	states := make(chan ConnStateEvent, 100)
+	client.OnStateChange(func(event ConnStateEvent) {
+		states <- event
+		// 首次断开后关闭服务端，使重连失败
+		if event.State == StateDisconnected {
+			server.Close()
+		}
+	})
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	var got []ConnState
+	timeout := time.After(5 * time.Second)
+	for len(got) == 0 || got[len(got)-1] != StateGaveUp {
+		select {
+		case event := <-states:
+			got = append(got, event.State)
+			switch event.State {
+			case StateDisconnected:
+				if event.Err == nil {
+					t.Error("断开事件应携带错误")
+				}
+			case StateReconnecting:
+				if event.Attempt < 1 || event.Downtime < 0 {
+					t.Errorf("重连事件字段错误: %+v", event)
+				}
+			}
+		case <-timeout:
+			t.Fatalf("未收到放弃重连事件: %v", got)
+		}
+	}
+
+	want := []ConnState{StateConnected, StateDisconnected, StateReconnecting, StateReconnecting, StateGaveUp}
+	if len(got) != len(want) {
+		t.Fatalf("状态序列错误: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("状态序列错误: got %v, want %v", got, want)
+		}
+	}
+
+	if client.State() != StateGaveUp {
+		t.Errorf("State() 错误: got %v, want %v", client.State(), StateGaveUp)
+	}
+}
+
+func TestFailPendingOnDisconnect(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := r.Context()
+		n := conns.Add(1)
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var request ActionRequest
+			json.Unmarshal(data, &request)
+			// 收到指定动作后直接断开，不返回响应
+			if request.Action == "send_message" || (request.Action == "get_self_info" && n == 1) {
+				conn.Close(websocket.StatusGoingAway, "bye")
+				return
+			}
+			response, _ := json.Marshal(ActionResponse{
+				Status: "ok",
+				Data:   map[string]any{"user_id": "bot", "user_name": "bot"},
+				Echo:   request.Echo,
+			})
+			conn.Write(ctx, websocket.MessageText, response)
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// 不可重发的动作立即失败
+	client, _ := New(wsURL, WithReconnect(false), WithTimeout(10*time.Second))
+	defer client.Close()
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Call("send_message", nil); !errors.Is(err, ErrConnectionLost) {
+		t.Fatalf("应返回 ErrConnectionLost: got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("连接断开后未立即失败")
+	}
+
+	// 不会重连时可重发的动作同样立即失败
+	conns.Store(0)
+	noReconnect, _ := New(wsURL,
+		WithReconnect(false),
+		WithResendActions("get_self_info"),
+		WithTimeout(10*time.Second),
+	)
+	defer noReconnect.Close()
+	if err := noReconnect.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	start = time.Now()
+	if _, err := noReconnect.GetSelfInfo(); !errors.Is(err, ErrConnectionLost) {
+		t.Fatalf("应返回 ErrConnectionLost: got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("未启用重连时可重发的动作未立即失败")
+	}
+
+	// 可重发的动作在重连后重发
+	conns.Store(0)
+	resend, _ := New(wsURL,
+		WithResendActions("get_self_info"),
+		WithReconnectWait(10*time.Millisecond),
+		WithTimeout(5*time.Second),
+	)
+	defer resend.Close()
+	if err := resend.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	info, err := resend.GetSelfInfo()
+	if err != nil {
+		t.Fatalf("重连后重发失败: %v", err)
+	}
+	if info.UserID != "bot" {
+		t.Errorf("UserID 错误: got %s, want %s", info.UserID, "bot")
+	}
+}
+
+func TestHeartbeatWatchdog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		// 只发送一次心跳，之后保持连接但不再响应，模拟半开连接
+		heartbeat := `{"id":"1","time":1,"type":"meta","detail_type":"heartbeat","sub_type":"","interval":50}`
+		conn.Write(r.Context(), websocket.MessageText, []byte(heartbeat))
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := New("ws"+strings.TrimPrefix(server.URL, "http"), WithReconnect(false))
+	defer client.Close()
+
+	disconnected := make(chan struct{}, 1)
+	client.OnStateChange(func(event ConnStateEvent) {
+		if event.State == StateDisconnected {
+			disconnected <- struct{}{}
+		}
+	})
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("心跳超时后未断开连接")
+	}
+}
+
+func TestMetaConnect(t *testing.T) {
+	version := "12"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		connect := `{"id":"1","time":1,"type":"meta","detail_type":"connect","sub_type":"","version":{"impl":"test","version":"1.0.0","onebot_version":"` + version + `"}}`
+		conn.Write(r.Context(), websocket.MessageText, []byte(connect))
+		for {
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	connected := make(chan *MetaEvent, 1)
+	client, _ := New(wsURL, WithReconnect(false), WithVersionCheck(true))
+	defer client.Close()
+	client.On("meta.connect", func(event any) {
+		connected <- event.(*MetaEvent)
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到 meta.connect 事件")
+	}
+
+	info := client.ConnInfo()
+	if info == nil || info.Impl != "test" || info.OneBotVersion != "12" {
+		t.Fatalf("ConnInfo 错误: %+v", info)
+	}
+	if !client.IsConnected() {
+		t.Error("版本匹配时不应断开连接")
+	}
+
+	// 版本不匹配时断开连接并放弃重连
+	version = "11"
+	gaveUp := make(chan error, 1)
+	refused, _ := New(wsURL,
+		WithVersionCheck(true),
+		WithReconnectWait(10*time.Millisecond),
+		WithGiveUp(func(attempts int, err error) {
+			gaveUp <- err
+		}),
+	)
+	defer refused.Close()
+
+	states := make(chan ConnStateEvent, 10)
+	refused.OnStateChange(func(event ConnStateEvent) {
+		states <- event
+	})
+	if err := refused.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	select {
+	case err := <-gaveUp:
+		if !errors.Is(err, ErrVersionMismatch) {
+			t.Errorf("应返回 ErrVersionMismatch: got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("版本不匹配时未放弃重连")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	var got []ConnState
+	for len(states) > 0 {
+		event := <-states
+		got = append(got, event.State)
+		if event.State != StateConnected && !errors.Is(event.Err, ErrVersionMismatch) {
+			t.Errorf("%v 事件应携带 ErrVersionMismatch: %v", event.State, event.Err)
+		}
+	}
+	want := []ConnState{StateConnected, StateDisconnected, StateGaveUp}
+	if !slices.Equal(got, want) {
+		t.Errorf("状态序列错误: got %v, want %v", got, want)
+	}
+}
+
+func TestSkipAbandonedActions(t *testing.T) {
+	var actions []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		for {
+			_, data, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			var request ActionRequest
+			json.Unmarshal(data, &request)
+			mu.Lock()
+			actions = append(actions, request.Action)
+			mu.Unlock()
+			response, _ := json.Marshal(ActionResponse{Status: "ok", Data: map[string]any{}, Echo: request.Echo})
+			conn.Write(r.Context(), websocket.MessageText, response)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := New("ws"+strings.TrimPrefix(server.URL, "http"), WithReconnect(false))
+	defer client.Close()
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+
+	// 模拟调用方已超时：动作仍在队列中，但已取消注册
+	abandoned := NewActionRequest("send_message", nil)
+	abandoned.Echo = "abandoned"
+	client.actionChan <- &actionCall{request: abandoned, response: make(chan *ActionResponse, 1), err: make(chan error, 1)}
+
+	if _, err := client.Call("get_self_info", nil); err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, action := range actions {
+		if action == "send_message" {
+			t.Fatalf("已放弃的动作不应发送: %v", actions)
+		}
+	}
+}
+
+func TestReverseRejectsSecondConnection(t *testing.T) {
+	client, _ := New("")
+	defer client.Close()
+
+	server := httptest.NewServer(client.ReverseHandler())
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 同时发起多个握手，只有一个能建立连接
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted []*websocket.Conn
+		rejected int
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, resp, err := websocket.Dial(ctx, wsURL, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				accepted = append(accepted, conn)
+			case resp != nil && resp.StatusCode == http.StatusConflict:
+				rejected++
+			default:
+				t.Errorf("反向 WebSocket 连接失败: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(accepted) != 1 || rejected != 4 {
+		t.Fatalf("同时握手时应只接受一个连接: accepted %d, rejected %d", len(accepted), rejected)
+	}
+
+	// 第一个连接断开后可以重新连接
+	accepted[0].Close(websocket.StatusNormalClosure, "")
+	for {
+		second, resp, err := websocket.Dial(ctx, wsURL, nil)
+		if err == nil {
+			second.CloseNow()
+			return
+		}
+		if resp == nil || resp.StatusCode != http.StatusConflict {
+			t.Fatalf("旧连接断开后重新连接失败: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("旧连接断开后仍拒绝新连接")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package onebot
+
+// eventKeys 返回事件匹配的处理器键，从通配到具体依次为
+// "*"、"type"、"type.detail_type"、"type.detail_type.sub_type"
+// 事件会分发给所有层级的处理器，例如群消息同时匹配 "message" 和 "message.group"，
+// 而不是只匹配 "type.detail_type" 一个键
+func eventKeys(event any) []string {
+	keys := []string{"*"}
+
+	e, ok := event.(interface{ Base() *Event })
+	if !ok {
+		return keys
+	}
+
+	base := e.Base()
+	if base.Type == "" {
+		return keys
+	}
+	keys = append(keys, base.Type)
+
+	if base.DetailType != "" {
+		keys = append(keys, base.Type+"."+base.DetailType)
+		if base.SubType != "" {
+			keys = append(keys, base.Type+"."+base.DetailType+"."+base.SubType)
+		}
+	}
+
+	return keys
+}
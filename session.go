@@ -0,0 +1,225 @@
+package onebot
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionBuffer 会话中等待读取的消息数量上限
+const sessionBuffer = 10
+
+// Session 多轮对话会话
+// 会话按 MessageEvent.SessionKey 区分（机器人账号、消息类型、群/频道和用户），
+// 会话存续期间，来自同一会话的消息只交给 Next，不再分发给订阅者、中间件和处理器。
+type Session struct {
+	client   *Client
+	key      string
+	event    *MessageEvent
+	messages chan *MessageEvent
+	done     chan struct{}
+	once     sync.Once
+	idle     *time.Timer
+
+	state map[string]any
+	mu    sync.RWMutex // 保护 state 和 idle
+}
+
+// StartSession 以 msg 所在的会话开始多轮对话，同一会话已存在时返回 ErrSessionActive
+// 会话在 WithSessionTimeout 设置的时间内既没有收到消息也没有调用 Next 时自动结束
+func (c *Client) StartSession(msg *MessageEvent) (*Session, error) {
+	key := msg.SessionKey()
+
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if _, ok := c.sessions[key]; ok {
+		return nil, ErrSessionActive
+	}
+
+	s := &Session{
+		client:   c,
+		key:      key,
+		event:    msg,
+		messages: make(chan *MessageEvent, sessionBuffer),
+		done:     make(chan struct{}),
+		state:    make(map[string]any),
+	}
+	if c.sessionTimeout > 0 {
+		s.mu.Lock()
+		s.idle = time.AfterFunc(c.sessionTimeout, s.Close)
+		s.mu.Unlock()
+	}
+	c.sessions[key] = s
+	return s, nil
+}
+
+// Session 返回 msg 所在会话中进行中的会话，不存在时返回 nil
+func (c *Client) Session(msg *MessageEvent) *Session {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+
+	return c.sessions[msg.SessionKey()]
+}
+
+// deliverSession 将消息交给进行中的会话，返回是否已被会话接收
+func (c *Client) deliverSession(msg *MessageEvent) bool {
+	c.sessionMu.RLock()
+	s, ok := c.sessions[msg.SessionKey()]
+	c.sessionMu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	select {
+	case s.messages <- msg:
+		s.touch()
+	case <-s.done:
+		return false
+	default:
+		c.logger.Warn("会话消息过多，丢弃消息", "session", s.key, "message_id", msg.MessageID)
+	}
+	return true
+}
+
+// Key 返回会话键
+func (s *Session) Key() string {
+	return s.key
+}
+
+// Event 返回开始会话的消息
+func (s *Session) Event() *MessageEvent {
+	return s.event
+}
+
+// Get 返回会话状态中 key 对应的值
+func (s *Session) Get(key string) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.state[key]
+}
+
+// Set 设置会话状态
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[key] = value
+}
+
+// Next 等待会话中的下一条消息
+// ctx 没有截止时间时使用 WithSessionTimeout 设置的超时时间，超时返回 ErrTimeout；
+// 收到取消关键词或会话已结束时返回 ErrSessionCanceled。超时、取消或 ctx 结束后会话随之结束。
+func (s *Session) Next(ctx context.Context) (*MessageEvent, error) {
+	// 等待期间由 ctx 控制超时，返回后重新开始空闲计时
+	s.stopIdle()
+	defer s.touch()
+
+	if _, ok := ctx.Deadline(); !ok && s.client.sessionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.client.sessionTimeout)
+		defer cancel()
+	}
+
+	select {
+	case msg := <-s.messages:
+		if s.client.isCancelKeyword(msg) {
+			s.Close()
+			return nil, ErrSessionCanceled
+		}
+		return msg, nil
+	case <-s.done:
+		return nil, ErrSessionCanceled
+	case <-s.client.ctx.Done():
+		s.Close()
+		return nil, ErrConnectionLost
+	case <-ctx.Done():
+		s.Close()
+		return nil, contextError(ctx.Err())
+	}
+}
+
+// Reply 向会话所在的群、频道或私聊发送消息
+func (s *Session) Reply(message Message) (*SendMessageResponse, error) {
+	return s.ReplyContext(context.Background(), message)
+}
+
+// ReplyContext 向会话所在的群、频道或私聊发送消息（带上下文）
+func (s *Session) ReplyContext(ctx context.Context, message Message) (*SendMessageResponse, error) {
+	params := map[string]any{"message": message}
+	switch {
+	case s.event.IsGroupMessage():
+		params["group_id"] = s.event.GroupID
+	case s.event.IsChannelMessage():
+		params["guild_id"] = s.event.GuildID
+		params["channel_id"] = s.event.ChannelID
+	default:
+		params["user_id"] = s.event.UserID
+	}
+
+	if s.event.Self != nil {
+		ctx = ContextWithSelf(ctx, *s.event.Self)
+	}
+	return s.client.SendMessageContext(ctx, s.event.DetailType, params)
+}
+
+// Done 返回会话结束时关闭的通道
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// touch 重新开始空闲计时
+func (s *Session) touch() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	select {
+	case <-s.done:
+	default:
+		if s.idle != nil {
+			s.idle.Reset(s.client.sessionTimeout)
+		}
+	}
+}
+
+// stopIdle 停止空闲计时
+func (s *Session) stopIdle() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+}
+
+// Close 结束会话，之后来自该会话的消息恢复正常分发，可重复调用
+func (s *Session) Close() {
+	s.once.Do(func() {
+		s.stopIdle()
+
+		s.client.sessionMu.Lock()
+		if s.client.sessions[s.key] == s {
+			delete(s.client.sessions, s.key)
+		}
+		s.client.sessionMu.Unlock()
+
+		close(s.done)
+	})
+}
+
+// isCancelKeyword 判断消息是否为取消关键词
+func (c *Client) isCancelKeyword(msg *MessageEvent) bool {
+	if len(c.cancelKeywords) == 0 {
+		return false
+	}
+
+	text := msg.AltMessage
+	if text == "" {
+		text = msg.Message.ToAltMessage()
+	}
+	return slices.Contains(c.cancelKeywords, strings.TrimSpace(text))
+}
@@ -0,0 +1,95 @@
+package onebot
+
+import (
+	"slices"
+	"time"
+)
+
+// ConnState 连接状态
+type ConnState int
+
+// 连接状态常量
+const (
+	StateIdle         ConnState = iota // 尚未连接
+	StateConnected                     // 已连接
+	StateDisconnected                  // 连接断开
+	StateReconnecting                  // 正在重连
+	StateGaveUp                        // 已放弃重连
+)
+
+// String 返回连接状态名称
+func (s ConnState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateGaveUp:
+		return "gave_up"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnStateEvent 连接状态变化
+type ConnStateEvent struct {
+	State    ConnState     // 新的连接状态
+	Attempt  int           // 重连次数：正在进行的（Reconnecting）、成功的（Connected）或已尝试的（GaveUp）
+	Err      error         // 导致断开的错误或最后一次重连失败的错误
+	Downtime time.Duration // 自连接断开以来经过的时间，首次连接时为 0
+}
+
+// OnStateChange 注册连接状态变化回调
+// 回调在状态变化的协程中同步调用，应尽快返回，耗时操作请自行启动协程
+func (c *Client) OnStateChange(fn func(ConnStateEvent)) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.stateHooks = append(c.stateHooks, fn)
+}
+
+// State 返回当前连接状态
+func (c *Client) State() ConnState {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	return c.state
+}
+
+// setState 更新连接状态并通知回调
+func (c *Client) setState(state ConnState, attempt int, err error) {
+	c.stateMu.Lock()
+	now := time.Now()
+	event := ConnStateEvent{
+		State:   state,
+		Attempt: attempt,
+		Err:     err,
+	}
+	if !c.downSince.IsZero() {
+		event.Downtime = now.Sub(c.downSince)
+	}
+
+	switch state {
+	case StateConnected:
+		event.Attempt = c.attempt
+		c.attempt = 0
+		c.downSince = time.Time{}
+	case StateDisconnected:
+		event.Downtime = 0
+		c.downSince = now
+	case StateReconnecting:
+		c.attempt = attempt
+	}
+
+	c.state = state
+	hooks := slices.Clone(c.stateHooks)
+	c.stateMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(event)
+	}
+}
@@ -0,0 +1,79 @@
+package onebot
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Subscription 事件处理器的订阅句柄
+type Subscription struct {
+	once   sync.Once
+	cancel func()
+}
+
+// Unsubscribe 注销处理器，可重复调用；已开始执行的处理器不受影响
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(s.cancel)
+}
+
+// handlerEntry 已注册的事件处理器
+type handlerEntry struct {
+	sub     *Subscription
+	handler HandlerFunc
+}
+
+// Once 注册只执行一次的事件处理器，eventType 的含义与 On 相同
+// 处理器在首个匹配的事件到达时自动注销
+func (c *Client) Once(eventType string, handler EventHandler) *Subscription {
+	return c.once(eventType, nil, handler)
+}
+
+// once 注册只处理一次满足 match 的事件的处理器，match 为空时匹配所有事件
+func (c *Client) once(eventType string, match func(event any) bool, handler EventHandler) *Subscription {
+	sub := &Subscription{}
+	var fired atomic.Bool
+	c.register(eventType, sub, func(event any) error {
+		if match != nil && !match(event) {
+			return nil
+		}
+		if fired.CompareAndSwap(false, true) {
+			sub.Unsubscribe()
+			handler(event)
+		}
+		return nil
+	})
+	return sub
+}
+
+// register 注册处理器并将订阅句柄绑定到该处理器
+func (c *Client) register(eventType string, sub *Subscription, handler HandlerFunc) {
+	sub.cancel = func() {
+		c.removeHandler(eventType, sub)
+	}
+
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+
+	c.eventHandlers[eventType] = append(c.eventHandlers[eventType], handlerEntry{sub: sub, handler: handler})
+}
+
+// removeHandler 注销订阅句柄对应的处理器
+// 分发中的事件可能仍持有旧的处理器列表，因此创建新的切片而不是原地修改
+func (c *Client) removeHandler(eventType string, sub *Subscription) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+
+	handlers := c.eventHandlers[eventType]
+	remaining := make([]handlerEntry, 0, len(handlers))
+	for _, entry := range handlers {
+		if entry.sub != sub {
+			remaining = append(remaining, entry)
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(c.eventHandlers, eventType)
+	} else {
+		c.eventHandlers[eventType] = remaining
+	}
+}
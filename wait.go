@@ -0,0 +1,123 @@
+package onebot
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// subscribeBuffer Subscribe 返回的事件通道的缓冲区大小
+const subscribeBuffer = 100
+
+// EventFilter 事件过滤函数，返回 true 表示匹配
+// 过滤函数在接收事件的协程中同步执行，应当尽快返回
+type EventFilter func(event any) bool
+
+// subscriber 事件流订阅者
+type subscriber struct {
+	filter EventFilter
+	ch     chan any
+}
+
+// Subscribe 订阅满足 filter 的事件流，filter 为空时订阅所有事件
+// 事件在中间件和处理器之前投递，不受工作池排队影响；通道缓冲区已满时丢弃新事件。
+// 调用返回的订阅句柄的 Unsubscribe 后通道关闭。
+func (c *Client) Subscribe(filter EventFilter) (<-chan any, *Subscription) {
+	return c.subscribe(filter, subscribeBuffer)
+}
+
+// WaitFor 等待下一个满足 filter 的事件，ctx 结束时返回的错误与 CallContext 一致
+// 可以在事件处理器中调用，用于多轮对话：
+//
+//	client.OnMessage(func(msg *onebot.MessageEvent) {
+//		client.SendGroupMessage(msg.GroupID, onebot.Message{onebot.Text("请输入名字")})
+//		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//		defer cancel()
+//		reply, err := client.WaitFor(ctx, onebot.SameSender(msg))
+//	})
+func (c *Client) WaitFor(ctx context.Context, filter EventFilter) (any, error) {
+	ch, sub := c.subscribe(filter, 1)
+	defer sub.Unsubscribe()
+
+	select {
+	case event := <-ch:
+		return event, nil
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	case <-c.ctx.Done():
+		return nil, ErrConnectionLost
+	}
+}
+
+// SameSender 返回匹配同一会话中同一用户所发消息的过滤函数
+func SameSender(msg *MessageEvent) EventFilter {
+	key := ConversationKey(msg)
+	return func(event any) bool {
+		reply, ok := event.(*MessageEvent)
+		return ok && reply.UserID == msg.UserID && ConversationKey(reply) == key
+	}
+}
+
+// subscribe 注册缓冲区大小为 size 的订阅者
+func (c *Client) subscribe(filter EventFilter, size int) (<-chan any, *Subscription) {
+	s := &subscriber{
+		filter: filter,
+		ch:     make(chan any, size),
+	}
+	sub := &Subscription{}
+	sub.cancel = func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+
+		delete(c.subscribers, sub)
+		close(s.ch)
+	}
+
+	c.subMu.Lock()
+	c.subscribers[sub] = s
+	c.subMu.Unlock()
+
+	return s.ch, sub
+}
+
+// publish 将事件投递给匹配的订阅者
+// 过滤函数在锁外执行，其中的 panic 会被捕获并交给错误处理函数
+func (c *Client) publish(event any) {
+	c.subMu.RLock()
+	subscribers := make(map[*Subscription]*subscriber, len(c.subscribers))
+	for sub, s := range c.subscribers {
+		subscribers[sub] = s
+	}
+	c.subMu.RUnlock()
+
+	for sub, s := range subscribers {
+		if !c.match(s.filter, event) {
+			continue
+		}
+
+		// 订阅者可能已在过滤期间注销，通道已关闭
+		c.subMu.RLock()
+		if c.subscribers[sub] == s {
+			select {
+			case s.ch <- event:
+			default:
+				c.logger.Warn("订阅者的事件通道已满，丢弃事件")
+			}
+		}
+		c.subMu.RUnlock()
+	}
+}
+
+// match 执行过滤函数，filter 为空时匹配所有事件，发生 panic 时视为不匹配
+func (c *Client) match(filter EventFilter, event any) (matched bool) {
+	if filter == nil {
+		return true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.reportError(event, &PanicError{Value: r, Stack: debug.Stack()})
+			matched = false
+		}
+	}()
+	return filter(event)
+}
@@ -0,0 +1,104 @@
+package onebot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// QuickActionFunc 快速操作函数
+// 根据收到的 Webhook 事件返回需要 OneBot 实现立即执行的动作请求，返回空表示无快速操作
+type QuickActionFunc func(event any) []*ActionRequest
+
+// WebhookHandler 返回 HTTP Webhook 事件接收处理器，可挂载到已有的 HTTP 服务中
+// 收到的事件会分发给 On 注册的处理器；若设置了 WithQuickAction，其返回的动作请求
+// 会作为响应体交由 OneBot 实现执行。被去重丢弃或被进行中的会话接收的事件不执行快速操作。
+func (c *Client) WebhookHandler() http.Handler {
+	return http.HandlerFunc(c.serveWebhook)
+}
+
+// serveWebhook 处理 OneBot 实现推送的 Webhook 请求
+func (c *Client) serveWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !c.authorized(r) {
+		c.logger.Warn("Webhook 鉴权失败", "remote", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Unsupported Media Type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.logger.Error("读取 Webhook 请求失败", "error", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	event, err := ParseEvent(data)
+	if err != nil {
+		c.logger.Error("解析事件失败", "error", err, "data", string(data))
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	// 重复推送的事件和被会话接收的消息不再执行快速操作，避免重试时重复回复
+	var actions []*ActionRequest
+	if c.handleEvent(event) && c.quickAction != nil {
+		actions = c.quickActions(r.Context(), event)
+	}
+
+	if len(actions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for _, action := range actions {
+		c.logger.Debug("返回快速操作", "action", action.Action)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(actions); err != nil {
+		c.logger.Error("发送快速操作失败", "error", err)
+	}
+}
+
+// quickActions 获取事件的快速操作，每个动作都经过 UseAction 注册的动作拦截器
+// 快速操作由 OneBot 实现在处理 Webhook 响应时执行，拦截器链末端无法得到执行结果，
+// 直接返回成功的响应；拦截器不调用 next 或返回错误时，该动作不会交给实现执行
+func (c *Client) quickActions(ctx context.Context, event any) []*ActionRequest {
+	c.interceptorMu.RLock()
+	interceptors := c.interceptors
+	c.interceptorMu.RUnlock()
+
+	var actions, pending []*ActionRequest
+	invoke := chainActions(func(ctx context.Context, request *ActionRequest) (*ActionResponse, error) {
+		pending = append(pending, request)
+		return &ActionResponse{Status: "ok", Echo: request.Echo}, nil
+	}, interceptors)
+
+	for _, request := range c.quickAction(event) {
+		if request == nil {
+			continue
+		}
+
+		pending = pending[:0]
+		if _, err := invoke(ctx, request); err != nil {
+			c.logger.Warn("快速操作被动作拦截器拒绝", "action", request.Action, "error", err)
+			continue
+		}
+		actions = append(actions, pending...)
+	}
+
+	return actions
+}